@@ -190,3 +190,34 @@ type BomComponentPolicyRule struct {
 	PolicyApprovalStatus string `json:"policyApprovalStatus"`
 	Comment              string `json:"comment"`
 }
+
+// bomComponentPolicyRule is the JSON form of BomComponentPolicyRule, with the fields of the policy rule
+// promoted next to its own
+type bomComponentPolicyRule struct {
+	policyRule
+	PolicyApprovalStatus string `json:"policyApprovalStatus"`
+	Comment              string `json:"comment"`
+}
+
+func (r *BomComponentPolicyRule) UnmarshalJSON(data []byte) error {
+	var rule bomComponentPolicyRule
+	if err := unmarshalKeepingUnknown(data, &rule, &rule.UnknownFields); err != nil {
+		return err
+	}
+
+	*r = BomComponentPolicyRule{
+		PolicyRule:           PolicyRule(rule.policyRule),
+		PolicyApprovalStatus: rule.PolicyApprovalStatus,
+		Comment:              rule.Comment,
+	}
+	return nil
+}
+
+func (r BomComponentPolicyRule) MarshalJSON() ([]byte, error) {
+	rule := bomComponentPolicyRule{
+		policyRule:           policyRule(r.PolicyRule),
+		PolicyApprovalStatus: r.PolicyApprovalStatus,
+		Comment:              r.Comment,
+	}
+	return marshalWithUnknown(rule, r.UnknownFields)
+}
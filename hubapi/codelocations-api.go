@@ -51,7 +51,7 @@ type ScanSummary struct {
 // Or maybe add something to the link itself to make the request?
 
 func (c *CodeLocation) GetScanSummariesLink() (*ResourceLink, error) {
-	return c.Meta.FindLinkByRel("scans")
+	return c.Meta.FindLinkByRel(CodeLocationRelScans)
 }
 
 func (c *CodeLocation) GetProjectVersionLink() (*ResourceLink, error) {
@@ -62,5 +62,5 @@ func (c *CodeLocation) GetProjectVersionLink() (*ResourceLink, error) {
 }
 
 func (s *ScanSummary) GetCodeLocationLink() (*ResourceLink, error) {
-	return s.Meta.FindLinkByRel("codelocation")
+	return s.Meta.FindLinkByRel(ScanSummaryRelCodeLocation)
 }
@@ -99,16 +99,27 @@ type ComponentProjectReference struct {
 
 type Component struct {
 	bdJsonComponentDetailV4
-	Name                string   `json:"name"`
-	Description         string   `json:"description,omitempty"`
-	ApprovalStatus      string   `json:"approvalStatus"`
-	Homepage            string   `json:"url,omitempty"`
-	AdditionalHomepages []string `json:"additionalHomepages"`
-	PrimaryLanguage     string   `json:"primaryLanguage,omitempty"`
-	Source              string   `json:"source"`
-	Type                string   `json:"type"`
-	Notes               string   `json:"notes,omitempty"`
-	Meta                Meta     `json:"_meta"`
+	Name                string        `json:"name"`
+	Description         string        `json:"description,omitempty"`
+	ApprovalStatus      string        `json:"approvalStatus"`
+	Homepage            string        `json:"url,omitempty"`
+	AdditionalHomepages []string      `json:"additionalHomepages"`
+	PrimaryLanguage     string        `json:"primaryLanguage,omitempty"`
+	Source              string        `json:"source"`
+	Type                string        `json:"type"`
+	Notes               string        `json:"notes,omitempty"`
+	Meta                Meta          `json:"_meta"`
+	UnknownFields       UnknownFields `json:"-"`
+}
+
+func (c *Component) UnmarshalJSON(data []byte) error {
+	type component Component
+	return unmarshalKeepingUnknown(data, (*component)(c), &c.UnknownFields)
+}
+
+func (c Component) MarshalJSON() ([]byte, error) {
+	type component Component
+	return marshalWithUnknown(component(c), c.UnknownFields)
 }
 
 type ComponentRequest struct {
@@ -19,9 +19,20 @@ const (
 )
 
 type ExternalExtension struct {
-	Name          string `json:"name"`
-	Description   string `json:"description"`
-	InfoURL       string `json:"infoUrl"`
-	Authenticated bool   `json:"authenticated"`
-	Meta          Meta   `json:"_meta"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description"`
+	InfoURL       string        `json:"infoUrl"`
+	Authenticated bool          `json:"authenticated"`
+	Meta          Meta          `json:"_meta"`
+	UnknownFields UnknownFields `json:"-"`
+}
+
+func (e *ExternalExtension) UnmarshalJSON(data []byte) error {
+	type externalExtension ExternalExtension
+	return unmarshalKeepingUnknown(data, (*externalExtension)(e), &e.UnknownFields)
+}
+
+func (e ExternalExtension) MarshalJSON() ([]byte, error) {
+	type externalExtension ExternalExtension
+	return marshalWithUnknown(externalExtension(e), e.UnknownFields)
 }
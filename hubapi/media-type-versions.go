@@ -0,0 +1,71 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubapi
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// MediaTypeVersion is one media type a resource is served as
+type MediaTypeVersion struct {
+	MediaType string
+	// Since is the first Black Duck release serving it, empty if every supported release does
+	Since string
+}
+
+// MediaTypeVersions is the compatibility table of the resources of this package, by the media type their
+// structs ask for: the media types each of them is served as, oldest first, with the release introducing
+// them. The versions of a family are not interchangeable between resources, project-detail-4 is a Project
+// while project-detail-5 is a ProjectVersion, so each resource lists only its own.
+var MediaTypeVersions = map[string][]MediaTypeVersion{
+	ContentTypeBdAdminV4: {{MediaType: ContentTypeBdAdminV4}},
+	ContentTypeBdAdminV5: {{MediaType: ContentTypeBdAdminV4}, {MediaType: ContentTypeBdAdminV5, Since: "2019.4.0"}},
+	ContentTypeBdBomV6: {
+		{MediaType: MediaType("bill-of-materials", 4)},
+		{MediaType: MediaType("bill-of-materials", 5), Since: "2018.12.0"},
+		{MediaType: ContentTypeBdBomV6, Since: "2019.12.0"},
+	},
+	ContentTypeBdComponentDetailV4: {{MediaType: ContentTypeBdComponentDetailV4}},
+	ContentTypeBdComponentDetailV5: {{MediaType: ContentTypeBdComponentDetailV4}, {MediaType: ContentTypeBdComponentDetailV5, Since: "2019.4.0"}},
+	ContentTypeBdPolicyV5:          {{MediaType: MediaType("policy", 4)}, {MediaType: ContentTypeBdPolicyV5, Since: "2019.4.0"}},
+	ContentTypeBdProjectDetailV4:   {{MediaType: ContentTypeBdProjectDetailV4}},
+	ContentTypeBdProjectDetailV5:   {{MediaType: ContentTypeBdProjectDetailV4}, {MediaType: ContentTypeBdProjectDetailV5, Since: "2019.4.0"}},
+	ContentTypeBdVulnerabilityV4:   {{MediaType: ContentTypeBdVulnerabilityV4}},
+}
+
+var versionedMediaType = regexp.MustCompile(`^application/vnd\.blackducksoftware\.([a-z-]+)-(\d+)\+json$`)
+
+// ParseMediaType splits a versioned Black Duck media type such as
+// application/vnd.blackducksoftware.bill-of-materials-6+json into its family and version
+func ParseMediaType(mediaType string) (family string, version int, ok bool) {
+	match := versionedMediaType.FindStringSubmatch(mediaType)
+	if match == nil {
+		return "", 0, false
+	}
+
+	version, err := strconv.Atoi(match[2])
+	if err != nil {
+		return "", 0, false
+	}
+
+	return match[1], version, true
+}
+
+// MediaType returns the media type of a version of a family, the reverse of ParseMediaType
+func MediaType(family string, version int) string {
+	return fmt.Sprintf("application/vnd.blackducksoftware.%s-%d+json", family, version)
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubapi
+
+import (
+	"testing"
+)
+
+func TestParseMediaType(t *testing.T) {
+	family, version, ok := ParseMediaType(ContentTypeBdBomV6)
+	if !ok || family != "bill-of-materials" || version != 6 {
+		t.Errorf("unexpected %s %d %v", family, version, ok)
+	}
+
+	if MediaType(family, version) != ContentTypeBdBomV6 {
+		t.Errorf("%s is not %s", MediaType(family, version), ContentTypeBdBomV6)
+	}
+
+	for _, mediaType := range []string{"application/json", ContentTypeRapidScanRequest, "application/vnd.blackducksoftware.bom+json"} {
+		if _, _, ok := ParseMediaType(mediaType); ok {
+			t.Errorf("%s should not be versioned", mediaType)
+		}
+	}
+}
+
+func TestMediaTypeVersionsKnowTheBuiltInTypes(t *testing.T) {
+	for _, mediaType := range []string{ContentTypeBdBomV6, ContentTypeBdProjectDetailV4, ContentTypeBdProjectDetailV5,
+		ContentTypeBdComponentDetailV5, ContentTypeBdPolicyV5, ContentTypeBdVulnerabilityV4, ContentTypeBdAdminV5} {
+		versions := MediaTypeVersions[mediaType]
+		if len(versions) == 0 {
+			t.Errorf("%s is missing from MediaTypeVersions", mediaType)
+			continue
+		}
+
+		// the structs ask for the newest version
+		if newest := versions[len(versions)-1]; newest.MediaType != mediaType {
+			t.Errorf("%s is not the newest version of itself but %s", mediaType, newest.MediaType)
+		}
+	}
+
+	if versions := MediaTypeVersions[ContentTypeBdProjectDetailV4]; len(versions) != 1 {
+		t.Errorf("a project is only served as %s, not %v", ContentTypeBdProjectDetailV4, versions)
+	}
+}
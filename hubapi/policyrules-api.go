@@ -49,6 +49,19 @@ type PolicyRule struct {
 	UpdatedBy     string           `json:"updatedBy"`
 	UpdatedByUser string           `json:"updatedByUser"`
 	Meta          Meta             `json:"_meta"`
+	UnknownFields UnknownFields    `json:"-"`
+}
+
+// policyRule is a PolicyRule without its JSON methods, which the structs embedding it would otherwise take
+// over and lose their own fields to
+type policyRule PolicyRule
+
+func (r *PolicyRule) UnmarshalJSON(data []byte) error {
+	return unmarshalKeepingUnknown(data, (*policyRule)(r), &r.UnknownFields)
+}
+
+func (r PolicyRule) MarshalJSON() ([]byte, error) {
+	return marshalWithUnknown(policyRule(r), r.UnknownFields)
 }
 
 type PolicyExpression struct {
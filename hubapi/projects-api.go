@@ -33,6 +33,23 @@ const (
 	ProjectVersionDistributionOpenSource = "OPENSOURCE"
 )
 
+// ProjectVersionPhases are the valid phases of a project version
+var ProjectVersionPhases = []string{
+	ProjectVersionPhasePlanning,
+	ProjectVersionPhaseDevelopment,
+	ProjectVersionPhaseReleased,
+	ProjectVersionPhaseDeprecated,
+	ProjectVersionPhaseArchived,
+}
+
+// ProjectVersionDistributions are the valid distributions of a project version
+var ProjectVersionDistributions = []string{
+	ProjectVersionDistributionExternal,
+	ProjectVersionDistributionSaaS,
+	ProjectVersionDistributionInternal,
+	ProjectVersionDistributionOpenSource,
+}
+
 const ContentTypeBdProjectDetailV4 = "application/vnd.blackducksoftware.project-detail-4+json"
 
 type bdJsonProjectDetailV4 struct{}
@@ -49,19 +66,30 @@ type ProjectList struct {
 
 type Project struct {
 	bdJsonProjectDetailV4
-	Name                    string     `json:"name"`
-	Description             string     `json:"description"`
-	Source                  string     `json:"source"`
-	ProjectTier             uint32     `json:"projectTier"`
-	ProjectLevelAdjustments bool       `json:"projectLevelAdjustments"`
-	ProjectOwner            string     `json:"projectOwner"`
-	CreatedAt               *time.Time `json:"createdAt,omitempty"`
-	CreatedBy               string     `json:"createdBy,omitempty"`
-	CreatedByUser           string     `json:"createdByUser,omitempty"`
-	UpdatedAt               *time.Time `json:"updatedAt,omitempty"`
-	UpdatedBy               string     `json:"updatedBy,omitempty"`
-	UpdatedByUser           string     `json:"updatedByUser,omitempty"`
-	Meta                    Meta       `json:"_meta"`
+	Name                    string        `json:"name"`
+	Description             string        `json:"description"`
+	Source                  string        `json:"source"`
+	ProjectTier             uint32        `json:"projectTier"`
+	ProjectLevelAdjustments bool          `json:"projectLevelAdjustments"`
+	ProjectOwner            string        `json:"projectOwner"`
+	CreatedAt               *time.Time    `json:"createdAt,omitempty"`
+	CreatedBy               string        `json:"createdBy,omitempty"`
+	CreatedByUser           string        `json:"createdByUser,omitempty"`
+	UpdatedAt               *time.Time    `json:"updatedAt,omitempty"`
+	UpdatedBy               string        `json:"updatedBy,omitempty"`
+	UpdatedByUser           string        `json:"updatedByUser,omitempty"`
+	Meta                    Meta          `json:"_meta"`
+	UnknownFields           UnknownFields `json:"-"`
+}
+
+func (p *Project) UnmarshalJSON(data []byte) error {
+	type project Project
+	return unmarshalKeepingUnknown(data, (*project)(p), &p.UnknownFields)
+}
+
+func (p Project) MarshalJSON() ([]byte, error) {
+	type project Project
+	return marshalWithUnknown(project(p), p.UnknownFields)
 }
 
 type ProjectRequest struct {
@@ -77,34 +105,46 @@ type ProjectRequest struct {
 	CustomSignatureDepth    *int                   `json:"customSignatureDepth,omitempty"`
 }
 
+// ProjectUpdate is a change to a Project, leaving the fields which are nil unchanged
+type ProjectUpdate struct {
+	Name                    *string
+	Description             *string
+	ProjectTier             *uint32
+	ProjectOwner            *string
+	ProjectLevelAdjustments *bool
+	// UpdatedAt, if set, is when the project was last updated as far as the caller knows. The update is
+	// refused if the project was updated since.
+	UpdatedAt *time.Time
+}
+
 func (p *Project) GetProjectVersionsLink() (*ResourceLink, error) {
-	return p.Meta.FindLinkByRel("versions")
+	return p.Meta.FindLinkByRel(ProjectRelVersions)
 }
 
 func (p *Project) GetProjectUsersLink() (*ResourceLink, error) {
-	return p.Meta.FindLinkByRel("users")
+	return p.Meta.FindLinkByRel(ProjectRelUsers)
 }
 
 func (v *ProjectVersion) GetProjectLink() (*ResourceLink, error) {
-	return v.Meta.FindLinkByRel("project")
+	return v.Meta.FindLinkByRel(ProjectVersionRelProject)
 }
 
 func (v *ProjectVersion) GetCodeLocationsLink() (*ResourceLink, error) {
-	return v.Meta.FindLinkByRel("codelocations")
+	return v.Meta.FindLinkByRel(ProjectVersionRelCodeLocations)
 }
 
 func (v *ProjectVersion) GetComponentsLink() (*ResourceLink, error) {
-	return v.Meta.FindLinkByRel("components")
+	return v.Meta.FindLinkByRel(ProjectVersionRelComponents)
 }
 
 func (v *ProjectVersion) GetVulnerableComponentsLink() (*ResourceLink, error) {
-	return v.Meta.FindLinkByRel("vulnerable-components")
+	return v.Meta.FindLinkByRel(ProjectVersionRelVulnerableComponents)
 }
 
 func (v *ProjectVersion) GetProjectVersionRiskProfileLink() (*ResourceLink, error) {
-	return v.Meta.FindLinkByRel("riskProfile")
+	return v.Meta.FindLinkByRel(ProjectVersionRelRiskProfile)
 }
 
 func (v *ProjectVersion) GetProjectVersionPolicyStatusLink() (*ResourceLink, error) {
-	return v.Meta.FindLinkByRel("policy-status")
+	return v.Meta.FindLinkByRel(ProjectVersionRelPolicyStatus)
 }
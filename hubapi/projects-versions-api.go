@@ -35,6 +35,17 @@ type ProjectVersion struct {
 	SettingUpdatedByUser string          `json:"settingUpdatedByUser"`
 	Source               string          `json:"source"`
 	Meta                 Meta            `json:"_meta"`
+	UnknownFields        UnknownFields   `json:"-"`
+}
+
+func (v *ProjectVersion) UnmarshalJSON(data []byte) error {
+	type projectVersion ProjectVersion
+	return unmarshalKeepingUnknown(data, (*projectVersion)(v), &v.UnknownFields)
+}
+
+func (v ProjectVersion) MarshalJSON() ([]byte, error) {
+	type projectVersion ProjectVersion
+	return marshalWithUnknown(projectVersion(v), v.UnknownFields)
 }
 
 type ProjectVersionRequest struct {
@@ -49,6 +60,19 @@ type ProjectVersionRequest struct {
 	CloneFromReleaseUrl string           `json:"cloneFromReleaseUrl,omitempty"`
 }
 
+// ProjectVersionUpdate is a change to a ProjectVersion, leaving the fields which are nil unchanged
+type ProjectVersionUpdate struct {
+	VersionName     *string
+	Nickname        *string
+	ReleaseComments *string
+	ReleasedOn      *time.Time
+	Phase           *string
+	Distribution    *string
+	// SettingUpdatedAt, if set, is when the version was last updated as far as the caller knows. The update
+	// is refused if the version was updated since.
+	SettingUpdatedAt *time.Time
+}
+
 // TODO: This is horrible, we need to fix up this API
 type ProjectVersionRiskProfile struct {
 	bdJsonBomV6
@@ -0,0 +1,63 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubapi
+
+// Relations of the links of a Project
+const (
+	ProjectRelVersions         = "versions"
+	ProjectRelUsers            = "users"
+	ProjectRelCanonicalVersion = "canonicalVersion"
+)
+
+// Relations of the links of a ProjectVersion
+const (
+	ProjectVersionRelProject              = "project"
+	ProjectVersionRelComponents           = "components"
+	ProjectVersionRelVulnerableComponents = "vulnerable-components"
+	ProjectVersionRelRiskProfile          = "riskProfile"
+	ProjectVersionRelPolicyStatus         = "policy-status"
+	ProjectVersionRelCodeLocations        = "codelocations"
+)
+
+// Relations of the links of a BomComponent
+const (
+	BomComponentRelVulnerabilities = "vulnerabilities"
+	BomComponentRelPolicyStatus    = "policy-status"
+	BomComponentRelPolicyRules     = "policy-rules"
+	BomComponentRelOrigins         = "origins"
+)
+
+// Relations of the links of a Component and a ComponentVersion
+const (
+	ComponentRelVersions               = "versions"
+	ComponentVersionRelVulnerabilities = "vulnerabilities"
+	ComponentVersionRelOrigins         = "origins"
+)
+
+// Relations of the links of a Vulnerability
+const (
+	VulnerabilityRelCwes = "cwes"
+)
+
+// Relations of the links of a CodeLocation and a ScanSummary
+const (
+	CodeLocationRelScans       = "scans"
+	ScanSummaryRelCodeLocation = "codelocation"
+)
+
+// Relations of the links of the current user
+const (
+	CurrentUserRelApiTokens = "api-tokens"
+)
@@ -0,0 +1,139 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// UnknownFields holds the JSON properties of a resource which its struct does not model, by name. They are
+// written back when the resource is marshalled again, so that a resource read from Black Duck and put back
+// keeps the settings this package does not know about.
+type UnknownFields map[string]json.RawMessage
+
+// unmarshalKeepingUnknown unmarshals data into v, a pointer to a struct, and the properties v has no field
+// for into unknown
+func unmarshalKeepingUnknown(data []byte, v interface{}, unknown *UnknownFields) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return err
+	}
+
+	*unknown = nil
+	known := knownNames(reflect.TypeOf(v).Elem())
+	for name, value := range properties {
+		if !isKnown(known, name) {
+			if *unknown == nil {
+				*unknown = make(UnknownFields)
+			}
+			(*unknown)[name] = value
+		}
+	}
+
+	return nil
+}
+
+// marshalWithUnknown marshals v, a struct, followed by the unknown properties it has no field for, sorted
+// by name
+func marshalWithUnknown(v interface{}, unknown UnknownFields) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(unknown) == 0 {
+		return data, err
+	}
+
+	known := knownNames(reflect.TypeOf(v))
+	names := make([]string, 0, len(unknown))
+	for name := range unknown {
+		if !isKnown(known, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for _, name := range names {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(unknown[name])
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+var knownNamesByType sync.Map
+
+// knownNames returns the JSON property names of the fields of struct type t
+func knownNames(t reflect.Type) []string {
+	if names, ok := knownNamesByType.Load(t); ok {
+		return names.([]string)
+	}
+
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			names = append(names, knownNames(field.Type)...)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+
+	knownNamesByType.Store(t, names)
+	return names
+}
+
+// isKnown reports whether the property name sets one of the fields named known, which encoding/json
+// matches regardless of case
+func isKnown(known []string, name string) bool {
+	for _, k := range known {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+	return false
+}
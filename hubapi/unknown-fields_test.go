@@ -0,0 +1,132 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+var extensionJSON = `{
+    "name": "jira",
+    "description": "Jira integration",
+    "infoUrl": "https://blackduck/jira",
+    "authenticated": true,
+    "_meta": {"href": "https://blackduck/api/external-extensions/1", "links": []},
+    "Description": "set into the description field, keys are matched regardless of case",
+    "settings": {"pollInterval": 30, "projects": ["a", "b"]},
+    "enabled": false
+}`
+
+func TestUnknownFieldsRoundTrip(t *testing.T) {
+	var extension ExternalExtension
+	if err := parseJSON(extensionJSON, &extension); err != nil {
+		t.Fatal("unable to parse json: " + err.Error())
+	}
+
+	if len(extension.UnknownFields) != 2 || string(extension.UnknownFields["enabled"]) != "false" {
+		t.Errorf("unexpected unknown fields %v", extension.UnknownFields)
+	}
+
+	extension.InfoURL = "https://blackduck/jira/info"
+	data, err := json.Marshal(&extension)
+	if err != nil {
+		t.Fatal("unable to marshal: " + err.Error())
+	}
+
+	var written, expected map[string]interface{}
+	parseJSON(string(data), &written)
+	parseJSON(extensionJSON, &expected)
+	expected["infoUrl"] = "https://blackduck/jira/info"
+	expected["description"] = "set into the description field, keys are matched regardless of case"
+	delete(expected, "Description")
+	expected["_meta"] = map[string]interface{}{"allow": nil, "href": "https://blackduck/api/external-extensions/1", "links": []interface{}{}}
+
+	if !reflect.DeepEqual(written, expected) {
+		t.Errorf("unexpected %s", data)
+	}
+}
+
+func TestUnknownFieldsOfEveryResource(t *testing.T) {
+	for _, resource := range []interface{}{&Project{}, &ProjectVersion{}, &Component{}, &PolicyRule{}, &User{}, &ExternalExtension{}, &BomComponentPolicyRule{}} {
+		if err := parseJSON(`{"_meta": {"href": "https://blackduck/api/1"}, "future": {"x": 1}}`, resource); err != nil {
+			t.Fatalf("unable to parse %T: %s", resource, err)
+		}
+
+		data, err := json.Marshal(resource)
+		if err != nil {
+			t.Fatalf("unable to marshal %T: %s", resource, err)
+		}
+
+		var written map[string]json.RawMessage
+		parseJSON(string(data), &written)
+		if string(written["future"]) != `{"x":1}` {
+			t.Errorf("%T lost the unknown field: %s", resource, data)
+		}
+
+		// marshalling a value rather than a pointer keeps them as well
+		value, _ := json.Marshal(reflect.ValueOf(resource).Elem().Interface())
+		if string(value) != string(data) {
+			t.Errorf("%T marshals differently by value: %s", resource, value)
+		}
+	}
+}
+
+func TestNoUnknownFields(t *testing.T) {
+	var project Project
+	if err := parseJSON(`{"name": "demo", "projectTier": 2}`, &project); err != nil {
+		t.Fatal("unable to parse json: " + err.Error())
+	}
+
+	if project.UnknownFields != nil || project.Name != "demo" || project.ProjectTier != 2 {
+		t.Errorf("unexpected %+v", project)
+	}
+}
+
+func TestBomComponentPolicyRuleRoundTrip(t *testing.T) {
+	ruleJSON := `{
+	    "name": "no GPL",
+	    "severity": "MAJOR",
+	    "policyApprovalStatus": "IN_VIOLATION_OVERRIDDEN",
+	    "comment": "approved by legal",
+	    "_meta": {"href": "https://blackduck/api/policy-rules/1"},
+	    "future": {"x": 1}
+	}`
+
+	var rule BomComponentPolicyRule
+	if err := parseJSON(ruleJSON, &rule); err != nil {
+		t.Fatal("unable to parse json: " + err.Error())
+	}
+
+	if rule.Name != "no GPL" || rule.PolicyApprovalStatus != "IN_VIOLATION_OVERRIDDEN" || rule.Comment != "approved by legal" {
+		t.Errorf("unexpected %+v", rule)
+	}
+	if len(rule.UnknownFields) != 1 || string(rule.UnknownFields["future"]) != `{"x": 1}` {
+		t.Errorf("unexpected unknown fields %v", rule.UnknownFields)
+	}
+
+	data, err := json.Marshal(&rule)
+	if err != nil {
+		t.Fatal("unable to marshal: " + err.Error())
+	}
+
+	var written map[string]interface{}
+	parseJSON(string(data), &written)
+	if written["policyApprovalStatus"] != "IN_VIOLATION_OVERRIDDEN" || written["comment"] != "approved by legal" ||
+		written["name"] != "no GPL" || written["future"] == nil {
+		t.Errorf("unexpected %s", data)
+	}
+}
@@ -19,6 +19,7 @@ const (
 	SsoStatusApi         = "/api/sso/status"
 	MfaStatusApi         = "/api/mfa/status"
 	UsersApi             = "/api/users"
+	LicensesApi          = "/api/licenses"
 	VulnerabilitiesApi   = "/api/vulnerabilities"
 	ReadinessApi         = "/api/health-checks/readiness"
 	LivenessApi          = "/api/health-checks/liveness"
@@ -33,11 +33,22 @@ type UserList struct {
 }
 
 type User struct {
-	User      string `json:"user"`
-	UserName  string `json:"userName"`
-	FirstName string `json:"firstName"`
-	LastName  string `json:"lastName"`
-	Email     string `json:"email"`
-	Active    bool   `json:"active"`
-	Meta      Meta   `json:"_meta"`
+	User          string        `json:"user"`
+	UserName      string        `json:"userName"`
+	FirstName     string        `json:"firstName"`
+	LastName      string        `json:"lastName"`
+	Email         string        `json:"email"`
+	Active        bool          `json:"active"`
+	Meta          Meta          `json:"_meta"`
+	UnknownFields UnknownFields `json:"-"`
+}
+
+func (u *User) UnmarshalJSON(data []byte) error {
+	type user User
+	return unmarshalKeepingUnknown(data, (*user)(u), &u.UnknownFields)
+}
+
+func (u User) MarshalJSON() ([]byte, error) {
+	type user User
+	return marshalWithUnknown(user(u), u.UnknownFields)
 }
@@ -15,14 +15,15 @@
 package hubclient
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/blackducksoftware/hub-client-go/hubapi"
-	log "github.com/sirupsen/logrus"
 )
 
 type BearerTokenResponse struct {
@@ -36,68 +37,155 @@ func NewWithApiToken(baseURL string, apiToken string, debugFlags HubClientDebug,
 }
 
 func NewWithApiTokenAndClient(baseURL string, apiToken string, debugFlags HubClientDebug, client *http.Client) (*Client, error) {
-	if client == nil {
-		client = createHttpClient(time.Minute)
-	}
+	return NewWithApiTokenAndClientContext(context.Background(), baseURL, apiToken, debugFlags, client)
+}
 
-	url := hubapi.BuildUrl(baseURL, hubapi.AuthenticateApi)
+// NewWithApiTokenAndClientContext is NewWithApiTokenAndClient with the token authentication request bound to ctx.
+// The returned Client keeps the API token and uses it to get a new bearer token shortly before
+// the current one expires, or when the server rejects it with a 401.
+func NewWithApiTokenAndClientContext(ctx context.Context, baseURL string, apiToken string, debugFlags HubClientDebug, client *http.Client) (*Client, error) {
+	return NewContext(ctx, baseURL, WithDebugFlags(debugFlags), WithHTTPClient(client), WithCredentials(ApiTokenCredentials{Token: apiToken}))
+}
+
+// SetTokenRefreshMargin sets how long before its expiry the bearer token of an API token client is refreshed
+func (c *Client) SetTokenRefreshMargin(margin time.Duration) {
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
+	c.tokenRefreshMargin = margin
+}
 
-	req, err := http.NewRequest(http.MethodPost, url, nil)
+// authenticateWithApiToken exchanges the API token for a bearer token and a CSRF token
+func (c *Client) authenticateWithApiToken(ctx context.Context) error {
+	url := hubapi.BuildUrl(c.baseURL, hubapi.AuthenticateApi)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
 	if err != nil {
-		return nil, AnnotateHubClientError(err, "error creating API token request")
+		return AnnotateHubClientError(err, "error creating API token request")
 	}
 
-	tokenValue := fmt.Sprintf("token %s", apiToken)
+	tokenValue := fmt.Sprintf("token %s", c.apiToken)
 
 	req.Header.Add(HeaderNameAuthorization, tokenValue)
 
 	currentTime := time.Now()
 
-	resp, err := client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return nil, AnnotateHubClientError(err, "error logging in via API Token")
+		return AnnotateHubClientError(err, "error logging in via API Token")
 	}
 
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, HubClientStatusCodeErrorf(resp.StatusCode, "got a %d response instead of a %d", resp.StatusCode, http.StatusOK)
+		return HubClientStatusCodeErrorf(resp.StatusCode, "got a %d response instead of a %d", resp.StatusCode, http.StatusOK)
 	}
 
 	csrf := resp.Header.Get(HeaderNameCsrfToken)
 	if csrf == "" {
-		return nil, newHubClientError(nil, resp, "CSRF token not found", nil)
+		return newHubClientError(nil, resp, "CSRF token not found", nil)
 	}
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, AnnotateHubClientError(err, "error reading body")
+		return AnnotateHubClientError(err, "error reading body")
 	}
 
 	var bearerTokenResponse BearerTokenResponse
 
 	err = json.Unmarshal(body, &bearerTokenResponse)
 	if err != nil {
-		return nil, AnnotateHubClientError(err, "error decoding JSON")
+		return AnnotateHubClientError(err, "error decoding JSON")
 	}
 
 	if bearerTokenResponse.BearerToken == "" {
-		return nil, newHubClientError(body, resp, "bearer token not found", nil)
+		return newHubClientError(body, resp, "bearer token not found", nil)
 	}
 
 	bearerTokenExpiryInSecs := bearerTokenResponse.ExpiresInMilliseconds / 1000
 	bearerTokenExpiryTime := currentTime.Add(time.Duration(bearerTokenExpiryInSecs) * time.Second)
 
-	log.Debug("Logged in with auth token successfully")
-
-	return &Client{
-		httpClient:               client,
-		baseURL:                  baseURL,
-		authToken:                bearerTokenResponse.BearerToken,
-		useAuthToken:             true,
-		csrfToken:                csrf,
-		haveCsrfToken:            true,
-		debugFlags:               debugFlags,
-		authTokenExpiryInUnixSec: bearerTokenExpiryTime.Unix(),
-	}, nil
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
+
+	c.authToken = bearerTokenResponse.BearerToken
+	c.useAuthToken = true
+	c.csrfToken = csrf
+	c.haveCsrfToken = true
+	c.authTokenExpiryInUnixSec = bearerTokenExpiryTime.Unix()
+
+	return nil
+}
+
+// refreshBearerToken re-authenticates with the API token unless another goroutine
+// already replaced staleToken in the meantime
+func (c *Client) refreshBearerToken(ctx context.Context, staleToken string) error {
+	c.refreshLock.Lock()
+	defer c.refreshLock.Unlock()
+
+	if c.bearerToken() != staleToken {
+		return nil
+	}
+
+	c.log().Debug("Refreshing bearer token with API token")
+	c.observeAuthRefresh(hubapi.AuthenticateApi)
+
+	return AnnotateHubClientError(c.authenticateWithApiToken(ctx), "unable to refresh bearer token")
+}
+
+func (c *Client) bearerToken() string {
+	c.authLock.RLock()
+	defer c.authLock.RUnlock()
+	return c.authToken
+}
+
+// bearerTokenExpiring reports whether the bearer token expires within the refresh margin
+func (c *Client) bearerTokenExpiring() bool {
+	c.authLock.RLock()
+	defer c.authLock.RUnlock()
+	return c.authTokenExpiryInUnixSec > 0 && time.Now().Add(c.tokenRefreshMargin).Unix() >= c.authTokenExpiryInUnixSec
+}
+
+// usesBearerToken reports whether the request is authenticated with the Client's bearer token
+func usesBearerToken(req *http.Request) bool {
+	return strings.HasPrefix(req.Header.Get(HeaderNameAuthorization), "Bearer ")
+}
+
+// doWithTokenRefresh sends a bearer token authenticated request, refreshing the token beforehand if it
+// is about to expire, and once more if the server rejects it
+func (c *Client) doWithTokenRefresh(req *http.Request) (*http.Response, error) {
+	if c.bearerTokenExpiring() {
+		if err := c.refreshBearerToken(req.Context(), c.bearerToken()); err != nil {
+			c.log().Warn("Unable to refresh bearer token before it expires, using the current one", errorField(err))
+		}
+	}
+
+	c.setAuthHeaders(req)
+	token := c.bearerToken()
+
+	resp, err := c.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// the body is gone, let the caller deal with the 401
+		return resp, nil
+	}
+
+	discardResponse(resp)
+
+	if err := c.refreshBearerToken(req.Context(), token); err != nil {
+		return nil, err
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, AnnotateHubClientError(err, "unable to replay request body")
+		}
+	}
+
+	c.setAuthHeaders(retry)
+
+	return c.send(retry)
 }
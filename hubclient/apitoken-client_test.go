@@ -15,11 +15,19 @@
 package hubclient
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/blackducksoftware/hub-client-go/hubapi"
 	"github.com/h2non/gock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewWithApiToken(t *testing.T) {
@@ -73,3 +81,99 @@ func TestNewWithApiTokenAndClient(t *testing.T) {
 	assert.True(t, client.haveCsrfToken)
 	assert.Equal(t, "csrf-fake-value", client.csrfToken)
 }
+
+// tokenServer issues a new bearer token for every authentication and only accepts the latest one
+type tokenServer struct {
+	lock            sync.Mutex
+	authentications int
+	currentToken    string
+	expiresInMs     int64
+}
+
+func (s *tokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if r.URL.Path == hubapi.AuthenticateApi {
+		s.authentications++
+		s.currentToken = fmt.Sprintf("bearer-%d", s.authentications)
+		w.Header().Set(HeaderNameCsrfToken, fmt.Sprintf("csrf-%d", s.authentications))
+		json.NewEncoder(w).Encode(BearerTokenResponse{BearerToken: s.currentToken, ExpiresInMilliseconds: s.expiresInMs})
+		return
+	}
+
+	if r.Header.Get(HeaderNameAuthorization) != "Bearer "+s.currentToken {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	json.NewEncoder(w).Encode(hubapi.CurrentVersion{Version: string(body)})
+}
+
+func (s *tokenServer) expireToken() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.currentToken = "expired"
+}
+
+func newTokenServerClient(t *testing.T, ts *tokenServer) (*Client, *httptest.Server) {
+	server := httptest.NewServer(ts)
+	t.Cleanup(server.Close)
+
+	client, err := NewWithApiTokenAndClient(server.URL, "api-token", 0, server.Client())
+	require.NoError(t, err)
+
+	return client, server
+}
+
+func TestClient_RefreshesBearerTokenAfterUnauthorized(t *testing.T) {
+	ts := &tokenServer{expiresInMs: time.Hour.Milliseconds()}
+	client, server := newTokenServerClient(t, ts)
+
+	ts.expireToken()
+
+	var version hubapi.CurrentVersion
+	_, err := client.HttpPostJSONExpectResult(server.URL, "payload", &version, "application/json", http.StatusOK)
+
+	require.NoError(t, err)
+	assert.Equal(t, "\"payload\"\n", version.Version)
+	assert.Equal(t, 2, ts.authentications)
+	assert.Equal(t, "bearer-2", client.authToken)
+	assert.Equal(t, "csrf-2", client.csrfToken)
+}
+
+func TestClient_RefreshesBearerTokenBeforeExpiry(t *testing.T) {
+	ts := &tokenServer{expiresInMs: 30 * time.Second.Milliseconds()}
+	client, server := newTokenServerClient(t, ts)
+
+	err := client.HttpGetJSON(server.URL, &hubapi.CurrentVersion{}, http.StatusOK)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, ts.authentications, "token expiring within the refresh margin was not refreshed")
+
+	client.SetTokenRefreshMargin(time.Second)
+	err = client.HttpGetJSON(server.URL, &hubapi.CurrentVersion{}, http.StatusOK)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, ts.authentications)
+}
+
+func TestClient_RefreshesBearerTokenOnceForConcurrentRequests(t *testing.T) {
+	ts := &tokenServer{expiresInMs: time.Hour.Milliseconds()}
+	client, server := newTokenServerClient(t, ts)
+
+	ts.expireToken()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, client.HttpGetJSON(server.URL, &hubapi.CurrentVersion{}, http.StatusOK))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 2, ts.authentications)
+}
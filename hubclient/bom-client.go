@@ -15,17 +15,21 @@
 package hubclient
 
 import (
+	"context"
 	"github.com/blackducksoftware/hub-client-go/hubapi"
-	log "github.com/sirupsen/logrus"
 )
 
 func (c *Client) ListProjectVersionComponents(link hubapi.ResourceLink) (*hubapi.BomComponentList, error) {
+	return c.ListProjectVersionComponentsContext(context.Background(), link)
+}
+
+func (c *Client) ListProjectVersionComponentsContext(ctx context.Context, link hubapi.ResourceLink) (*hubapi.BomComponentList, error) {
 
 	// Need offset/limit
 	// Should we abstract list fetching like we did with a single Get?
 
 	var bomList hubapi.BomComponentList
-	err := c.GetPage(link.Href, nil, &bomList)
+	err := c.GetPageContext(ctx, link.Href, nil, &bomList)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error while trying to get Project Version Component list")
@@ -36,12 +40,16 @@ func (c *Client) ListProjectVersionComponents(link hubapi.ResourceLink) (*hubapi
 
 // TODO: Should this be used?
 func (c *Client) ListProjectVersionVulnerableComponents(link hubapi.ResourceLink) (*hubapi.BomVulnerableComponentList, error) {
+	return c.ListProjectVersionVulnerableComponentsContext(context.Background(), link)
+}
+
+func (c *Client) ListProjectVersionVulnerableComponentsContext(ctx context.Context, link hubapi.ResourceLink) (*hubapi.BomVulnerableComponentList, error) {
 
 	// Need offset/limit
 	// Should we abstract list fetching like we did with a single Get?
 
 	var bomList hubapi.BomVulnerableComponentList
-	err := c.GetPage(link.Href, nil, &bomList)
+	err := c.GetPageContext(ctx, link.Href, nil, &bomList)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve vulnerable components list")
@@ -51,29 +59,37 @@ func (c *Client) ListProjectVersionVulnerableComponents(link hubapi.ResourceLink
 }
 
 func (c *Client) CountProjectVersionVulnerableComponents(link hubapi.ResourceLink) (int, error) {
-	return c.Count(link.Href)
+	return c.CountProjectVersionVulnerableComponentsContext(context.Background(), link)
+}
+
+func (c *Client) CountProjectVersionVulnerableComponentsContext(ctx context.Context, link hubapi.ResourceLink) (int, error) {
+	return c.CountContext(ctx, link.Href)
 }
 
 func (c *Client) ListAllProjectVersionVulnerableComponents(link hubapi.ResourceLink) ([]hubapi.BomVulnerableComponent, error) {
+	return c.ListAllProjectVersionVulnerableComponentsContext(context.Background(), link)
+}
 
-	totalCount, err := c.Count(link.Href)
+func (c *Client) ListAllProjectVersionVulnerableComponentsContext(ctx context.Context, link hubapi.ResourceLink) ([]hubapi.BomVulnerableComponent, error) {
+
+	totalCount, err := c.CountContext(ctx, link.Href)
 
 	if err != nil {
-		log.Errorf("Error trying to retrieve vulnerable components list: %+v.", err)
+		c.log().Error("Error trying to retrieve vulnerable components list", urlField(link.Href), errorField(err))
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve project's vulnerable components")
 	}
 
 	result := make([]hubapi.BomVulnerableComponent, 0, totalCount)
 
 	var bomPage hubapi.BomVulnerableComponentList
-	err = c.ForEachPage(link.Href, nil, &bomPage, func() error {
+	err = c.ForEachPageContext(ctx, link.Href, nil, &bomPage, func() error {
 		result = append(result, bomPage.Items...)
 		return nil
 	})
 
 	if err != nil {
-		log.Errorf("Error trying to retrieve vulnerable components list: %+v.", err)
-		return nil, err
+		c.log().Error("Error trying to retrieve vulnerable components list", urlField(link.Href), errorField(err))
+		return nil, AnnotateHubClientError(err, "Error trying to retrieve vulnerable components list")
 	}
 
 	return result, nil
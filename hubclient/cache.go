@@ -0,0 +1,300 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+)
+
+// CachedResponse is a successful GET response kept by a Cache
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// StoredAt is when the response was received or last revalidated
+	StoredAt time.Time
+}
+
+// Cache stores responses to GET requests. Implementations must be safe for concurrent use. A Cache may be
+// shared by several Clients: the responses are kept by the credentials they were requested with, so that a
+// Client is never answered with what the server gave to another user.
+type Cache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, response *CachedResponse)
+	Delete(key string)
+}
+
+// CachePolicy sets how long the responses of the GET requests whose URL path starts with PathPrefix, on whole
+// path segments, are used without asking the server. Once that time has passed, a response which came with an ETag or
+// Last-Modified header is revalidated with a conditional request, and used again if the server answers
+// 304 Not Modified. Caching only suits resources which are not changed through the Client, such as those
+// of the knowledge base.
+type CachePolicy struct {
+	PathPrefix string
+	// TTL is how long a response is used without asking the server, zero means every use is revalidated
+	TTL time.Duration
+	// Disabled turns caching off for the requests matching PathPrefix, to exclude them from a broader policy
+	Disabled bool
+}
+
+// DefaultCachePolicies caches vulnerabilities, components, component versions and licenses for an hour
+func DefaultCachePolicies() []CachePolicy {
+	return []CachePolicy{
+		{PathPrefix: hubapi.VulnerabilitiesApi, TTL: time.Hour},
+		{PathPrefix: hubapi.ComponentsApi, TTL: time.Hour},
+		{PathPrefix: hubapi.LicensesApi, TTL: time.Hour},
+	}
+}
+
+// SetCache makes the Client keep the responses of GET requests matching one of the policies in cache,
+// the first policy matching a request applies. Without policies DefaultCachePolicies are used.
+// A nil cache disables caching. SetCache must not be called while requests are in flight.
+func (c *Client) SetCache(cache Cache, policies ...CachePolicy) {
+	if len(policies) == 0 {
+		policies = DefaultCachePolicies()
+	}
+
+	c.cache = cache
+	c.cachePolicies = policies
+}
+
+// WithCache sets the response cache of the Client, see SetCache
+func WithCache(cache Cache, policies ...CachePolicy) Option {
+	return func(o *clientOptions) error {
+		o.cache = cache
+		o.cachePolicies = policies
+		return nil
+	}
+}
+
+func (c *Client) cachePolicy(req *http.Request) (CachePolicy, bool) {
+	if c.cache == nil || req.Method != http.MethodGet {
+		return CachePolicy{}, false
+	}
+
+	for _, policy := range c.cachePolicies {
+		if hasPathPrefix(req.URL.Path, policy.PathPrefix) {
+			return policy, !policy.Disabled
+		}
+	}
+
+	return CachePolicy{}, false
+}
+
+// cacheKey tells apart representations of the same resource requested with different media types, and the
+// responses given to different users
+func (c *Client) cacheKey(req *http.Request) string {
+	return req.URL.String() + " " + strings.Join(req.Header.Values(HeaderNameAccept), ",") + " " + c.identity()
+}
+
+// identity returns a digest of the credentials the Client authenticates with, empty if it has none
+func (c *Client) identity() string {
+	c.authLock.RLock()
+	defer c.authLock.RUnlock()
+
+	var credentials string
+	switch {
+	case c.apiToken != "":
+		credentials = "api token " + c.apiToken
+	case c.sessionUser != "":
+		credentials = "user " + c.sessionUser
+	case c.authToken != "":
+		credentials = "bearer token " + c.authToken
+	default:
+		return ""
+	}
+
+	// the key may end up outside of the process, the credentials must not
+	digest := sha256.Sum256([]byte(credentials))
+	return hex.EncodeToString(digest[:16])
+}
+
+// doCached answers GET requests from the cache where its policy allows, revalidating stale responses
+func (c *Client) doCached(req *http.Request) (*http.Response, error) {
+	policy, ok := c.cachePolicy(req)
+	if !ok {
+		return c.doAuthenticated(req)
+	}
+
+	key := c.cacheKey(req)
+	cached, found := c.cache.Get(key)
+	if found && time.Since(cached.StoredAt) < policy.TTL {
+		return cached.response(req), nil
+	}
+
+	if found {
+		etag, lastModified := cached.Header.Get(HeaderNameETag), cached.Header.Get(HeaderNameLastModified)
+		if etag == "" && lastModified == "" {
+			found = false
+		} else {
+			req = req.Clone(req.Context())
+			if etag != "" {
+				req.Header.Set(HeaderNameIfNoneMatch, etag)
+			}
+			if lastModified != "" {
+				req.Header.Set(HeaderNameIfModifiedSince, lastModified)
+			}
+		}
+	}
+
+	resp, err := c.doAuthenticated(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if found && resp.StatusCode == http.StatusNotModified {
+		discardResponse(resp)
+
+		revalidated := *cached
+		revalidated.StoredAt = time.Now()
+		c.cache.Set(key, &revalidated)
+
+		return revalidated.response(req), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || strings.Contains(resp.Header.Get(HeaderNameCacheControl), "no-store") {
+		return resp, nil
+	}
+
+	body, err := readBytes(resp.Body, resp.ContentLength)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	c.cache.Set(key, &CachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		StoredAt:   time.Now(),
+	})
+
+	return resp, nil
+}
+
+// response builds a response to the request from the cached one
+func (r *CachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(r.StatusCode),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// LRUCache is an in-memory Cache holding up to a maximum number of responses, each for at most a maximum age.
+// When full, the least recently used response is dropped.
+type LRUCache struct {
+	maxEntries int
+	maxAge     time.Duration
+
+	lock    sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key      string
+	response *CachedResponse
+	expires  time.Time
+}
+
+// NewLRUCache creates an LRUCache for up to maxEntries responses, each kept for at most maxAge.
+// A maxAge of zero keeps responses until they are dropped to make room.
+func NewLRUCache(maxEntries int, maxAge time.Duration) *LRUCache {
+	return &LRUCache{
+		maxEntries: maxEntries,
+		maxAge:     maxAge,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+func (l *LRUCache) Get(key string) (*CachedResponse, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	element, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		l.remove(element)
+		return nil, false
+	}
+
+	l.order.MoveToFront(element)
+	return entry.response, true
+}
+
+func (l *LRUCache) Set(key string, response *CachedResponse) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	entry := &lruEntry{key: key, response: response}
+	if l.maxAge > 0 {
+		entry.expires = time.Now().Add(l.maxAge)
+	}
+
+	if element, ok := l.entries[key]; ok {
+		element.Value = entry
+		l.order.MoveToFront(element)
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(entry)
+
+	for l.maxEntries > 0 && l.order.Len() > l.maxEntries {
+		l.remove(l.order.Back())
+	}
+}
+
+func (l *LRUCache) Delete(key string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if element, ok := l.entries[key]; ok {
+		l.remove(element)
+	}
+}
+
+// Len returns the number of responses in the cache
+func (l *LRUCache) Len() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.order.Len()
+}
+
+func (l *LRUCache) remove(element *list.Element) {
+	l.order.Remove(element)
+	delete(l.entries, element.Value.(*lruEntry).key)
+}
@@ -0,0 +1,214 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// vulnerabilityServer serves a single vulnerability with an ETag and counts the requests it gets
+type vulnerabilityServer struct {
+	lock        sync.Mutex
+	etag        string
+	requests    int
+	conditional int
+}
+
+func (s *vulnerabilityServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.requests++
+	if s.etag != "" {
+		w.Header().Set(HeaderNameETag, s.etag)
+	}
+
+	if match := r.Header.Get(HeaderNameIfNoneMatch); match != "" {
+		s.conditional++
+		if match == s.etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	w.Write([]byte(`{"name": "CVE-2021-44228", "severity": "CRITICAL"}`))
+}
+
+func (s *vulnerabilityServer) counts() (int, int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.requests, s.conditional
+}
+
+func newVulnerabilityServerClient(t *testing.T, vs *vulnerabilityServer, policies ...CachePolicy) (*Client, hubapi.ResourceLink) {
+	server := httptest.NewServer(vs)
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithCache(NewLRUCache(100, 0), policies...))
+	require.NoError(t, err)
+
+	return client, hubapi.ResourceLink{Href: server.URL + hubapi.VulnerabilitiesApi + "/CVE-2021-44228"}
+}
+
+func TestCacheServesFreshResponses(t *testing.T) {
+	vs := &vulnerabilityServer{etag: `"v1"`}
+	client, link := newVulnerabilityServerClient(t, vs)
+
+	for i := 0; i < 3; i++ {
+		vulnerability, err := client.GetVulnerability(link)
+		require.NoError(t, err)
+		assert.Equal(t, "CVE-2021-44228", vulnerability.Name)
+	}
+
+	requests, _ := vs.counts()
+	assert.Equal(t, 1, requests)
+}
+
+func TestCacheRevalidatesStaleResponses(t *testing.T) {
+	vs := &vulnerabilityServer{etag: `"v1"`}
+	client, link := newVulnerabilityServerClient(t, vs, CachePolicy{PathPrefix: hubapi.VulnerabilitiesApi})
+
+	for i := 0; i < 3; i++ {
+		vulnerability, err := client.GetVulnerability(link)
+		require.NoError(t, err)
+		assert.Equal(t, "CRITICAL", vulnerability.Severity)
+	}
+
+	requests, conditional := vs.counts()
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, 2, conditional)
+
+	vs.lock.Lock()
+	vs.etag = `"v2"`
+	vs.lock.Unlock()
+
+	_, err := client.GetVulnerability(link)
+	require.NoError(t, err)
+
+	cached, ok := client.cache.Get(client.cacheKey(mustRequest(t, link.Href)))
+	require.True(t, ok)
+	assert.Equal(t, `"v2"`, cached.Header.Get(HeaderNameETag))
+}
+
+func TestCacheWithoutValidatorsRefetches(t *testing.T) {
+	vs := &vulnerabilityServer{}
+	client, link := newVulnerabilityServerClient(t, vs, CachePolicy{PathPrefix: hubapi.VulnerabilitiesApi})
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetVulnerability(link)
+		require.NoError(t, err)
+	}
+
+	requests, conditional := vs.counts()
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, 0, conditional)
+}
+
+func TestCachePolicyMatching(t *testing.T) {
+	vs := &vulnerabilityServer{etag: `"v1"`}
+	client, link := newVulnerabilityServerClient(t, vs,
+		CachePolicy{PathPrefix: hubapi.VulnerabilitiesApi + "/CVE-2021-44228", Disabled: true},
+		CachePolicy{PathPrefix: hubapi.VulnerabilitiesApi, TTL: time.Hour})
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetVulnerability(link)
+		require.NoError(t, err)
+	}
+
+	requests, conditional := vs.counts()
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, 0, conditional)
+}
+
+func TestCachePolicyMatchesWholePathSegments(t *testing.T) {
+	client, err := New("https://blackduck", WithCache(NewLRUCache(100, 0), CachePolicy{PathPrefix: hubapi.ComponentsApi, TTL: time.Hour}))
+	require.NoError(t, err)
+
+	for path, matches := range map[string]bool{
+		hubapi.ComponentsApi:                 true,
+		hubapi.ComponentsApi + "/1/versions": true,
+		hubapi.ComponentsApi + "XYZ":         false,
+	} {
+		_, ok := client.cachePolicy(mustRequest(t, "https://blackduck"+path))
+		assert.Equal(t, matches, ok, path)
+	}
+}
+
+func TestCacheSharedByUsers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "` + r.Header.Get(HeaderNameAuthorization) + `"}`))
+	}))
+	defer server.Close()
+
+	cache := NewLRUCache(100, 0)
+	link := hubapi.ResourceLink{Href: server.URL + hubapi.VulnerabilitiesApi + "/CVE-2021-44228"}
+	for _, token := range []string{"alice", "bob", "alice"} {
+		client, err := New(server.URL, WithHTTPClient(server.Client()), WithCache(cache), WithCredentials(BearerTokenCredentials{Token: token}))
+		require.NoError(t, err)
+
+		vulnerability, err := client.GetVulnerability(link)
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer "+token, vulnerability.Name)
+	}
+}
+
+func TestLRUCacheEviction(t *testing.T) {
+	cache := NewLRUCache(2, 0)
+	cache.Set("a", &CachedResponse{})
+	cache.Set("b", &CachedResponse{})
+
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+
+	cache.Set("c", &CachedResponse{})
+	assert.Equal(t, 2, cache.Len())
+
+	_, ok = cache.Get("b")
+	assert.False(t, ok, "the least recently used entry was kept")
+	_, ok = cache.Get("a")
+	assert.True(t, ok)
+
+	cache.Delete("a")
+	_, ok = cache.Get("a")
+	assert.False(t, ok)
+}
+
+func TestLRUCacheMaxAge(t *testing.T) {
+	cache := NewLRUCache(0, 10*time.Millisecond)
+	cache.Set("a", &CachedResponse{})
+
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+
+	time.Sleep(20 * time.Millisecond)
+	_, ok = cache.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func mustRequest(t *testing.T, url string) *http.Request {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Add(HeaderNameAccept, hubapi.GetMimeType(&hubapi.Vulnerability{}))
+	return req
+}
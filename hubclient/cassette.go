@@ -0,0 +1,399 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// CassetteMode tells whether a Cassette records interactions with the server or replays recorded ones
+type CassetteMode int
+
+const (
+	// CassetteRecord sends every request to the server and saves it with its response
+	CassetteRecord CassetteMode = iota
+	// CassetteReplay answers every request with a recorded response, without any network access
+	CassetteReplay
+)
+
+// redacted replaces secrets in recorded interactions
+const redacted = "REDACTED"
+
+// CassetteMatching sets which parts of a request must be equal to those of a recorded one for it to be replayed
+type CassetteMatching struct {
+	Method bool
+	Path   bool
+	// Query compares the query parameters regardless of their order
+	Query bool
+	// Accept compares the media types of the Accept header
+	Accept bool
+	// Body compares the request bodies, such as the BDIO chunks uploaded by UploadBdioFilesByChunk
+	Body bool
+}
+
+// DefaultCassetteMatching matches requests on method, path, query and Accept header
+var DefaultCassetteMatching = CassetteMatching{Method: true, Path: true, Query: true, Accept: true}
+
+// Cassette is an http.RoundTripper which records the requests made by a Client and their responses into
+// a fixture directory, one JSON file per interaction, or replays them from there. Authorization, CSRF
+// and cookie headers, passwords, API tokens and bearer tokens are redacted before anything is written.
+//
+// When replaying, every recorded interaction is used once, in the order of recording: a request gets the
+// response of the first unused interaction it matches. Request bodies are recorded as well, so uploads
+// like those of UploadBdioFilesByChunk can be checked by matching on Body.
+type Cassette struct {
+	// Matching sets how requests are matched to recorded interactions, DefaultCassetteMatching if not changed
+	Matching CassetteMatching
+	// RedactHeaders are further headers whose values are redacted
+	RedactHeaders []string
+
+	dir  string
+	mode CassetteMode
+	next http.RoundTripper
+
+	lock         sync.Mutex
+	interactions []*cassetteInteraction
+	used         []bool
+}
+
+type cassetteInteraction struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+type cassetteRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	cassetteBody
+}
+
+type cassetteResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	cassetteBody
+}
+
+// cassetteBody keeps text bodies readable and base64 encodes binary ones
+type cassetteBody struct {
+	Body       string `json:"body,omitempty"`
+	BodyBase64 string `json:"bodyBase64,omitempty"`
+}
+
+func newCassetteBody(body []byte) cassetteBody {
+	if utf8.Valid(body) {
+		return cassetteBody{Body: string(body)}
+	}
+	return cassetteBody{BodyBase64: base64.StdEncoding.EncodeToString(body)}
+}
+
+func (b cassetteBody) bytes() []byte {
+	if b.BodyBase64 != "" {
+		body, _ := base64.StdEncoding.DecodeString(b.BodyBase64)
+		return body
+	}
+	return []byte(b.Body)
+}
+
+var fixtureName = regexp.MustCompile(`^\d{4}-.*\.json$`)
+
+// NewCassette creates a Cassette using the fixtures in dir. In CassetteRecord mode the directory is created
+// if necessary and the fixtures recorded before are removed; in CassetteReplay mode they are loaded.
+func NewCassette(dir string, mode CassetteMode) (*Cassette, error) {
+	c := &Cassette{
+		Matching: DefaultCassetteMatching,
+		dir:      dir,
+		mode:     mode,
+		next:     http.DefaultTransport,
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil && !(os.IsNotExist(err) && mode == CassetteRecord) {
+		return nil, AnnotateHubClientErrorf(err, "unable to read cassette %s", dir)
+	}
+
+	var names []string
+	for _, file := range files {
+		if !file.IsDir() && fixtureName.MatchString(file.Name()) {
+			names = append(names, file.Name())
+		}
+	}
+	sort.Strings(names)
+
+	if mode == CassetteRecord {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, AnnotateHubClientErrorf(err, "unable to create cassette %s", dir)
+		}
+		for _, name := range names {
+			if err := os.Remove(filepath.Join(dir, name)); err != nil {
+				return nil, AnnotateHubClientErrorf(err, "unable to remove old fixture %s", name)
+			}
+		}
+		return c, nil
+	}
+
+	for _, name := range names {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, AnnotateHubClientErrorf(err, "unable to read fixture %s", name)
+		}
+
+		var interaction cassetteInteraction
+		if err := json.Unmarshal(data, &interaction); err != nil {
+			return nil, AnnotateHubClientErrorf(err, "unable to parse fixture %s", name)
+		}
+
+		c.interactions = append(c.interactions, &interaction)
+	}
+	c.used = make([]bool, len(c.interactions))
+
+	return c, nil
+}
+
+// Wrap makes the Cassette send the requests it records through next, http.DefaultTransport by default
+func (c *Cassette) Wrap(next http.RoundTripper) http.RoundTripper {
+	// wrapping the cassette around itself would make it record forever
+	if next != nil && next != http.RoundTripper(c) {
+		c.next = next
+	}
+	return c
+}
+
+// WithCassette records or replays all requests of the Client with the cassette, wrapping its transport
+func WithCassette(cassette *Cassette) Option {
+	return func(o *clientOptions) error {
+		o.cassette = cassette
+		return nil
+	}
+}
+
+// Unused returns the number of recorded interactions which have not been replayed
+func (c *Cassette) Unused() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	unused := 0
+	for _, used := range c.used {
+		if !used {
+			unused++
+		}
+	}
+	return unused
+}
+
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// compressed bodies are recorded and matched decompressed, to keep them readable
+	body, _ = decompressBody(req.Header, body)
+
+	if c.mode == CassetteReplay {
+		return c.replay(req, body)
+	}
+
+	return c.record(req, body)
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := readBytes(req.Body, req.ContentLength)
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to read request body")
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func (c *Cassette) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := c.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	respBody, err := readBytes(resp.Body, resp.ContentLength)
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to read response body")
+	}
+	if decompressed, ok := decompressBody(resp.Header, respBody); ok {
+		respBody = decompressed
+		resp.Header.Del(HeaderNameContentEncoding)
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = int64(len(respBody))
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	requestHeader := c.redactHeader(req.Header)
+	requestHeader.Del(HeaderNameContentEncoding)
+
+	interaction := &cassetteInteraction{
+		Request: cassetteRequest{
+			Method:       req.Method,
+			URL:          req.URL.String(),
+			Header:       requestHeader,
+			cassetteBody: newCassetteBody(redactBody(body)),
+		},
+		Response: cassetteResponse{
+			StatusCode:   resp.StatusCode,
+			Header:       c.redactHeader(resp.Header),
+			cassetteBody: newCassetteBody(redactBody(respBody)),
+		},
+	}
+
+	data, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to encode interaction")
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.interactions = append(c.interactions, interaction)
+	name := fmt.Sprintf("%04d-%s%s.json", len(c.interactions), req.Method, fixtureSuffix(req.URL.Path))
+	if err := ioutil.WriteFile(filepath.Join(c.dir, name), data, 0644); err != nil {
+		return nil, errors.WithMessagef(err, "unable to write fixture %s", name)
+	}
+
+	return resp, nil
+}
+
+// fixtureSuffix turns a URL path into a file name part, /api/projects/{id} into -api-projects-id
+func fixtureSuffix(path string) string {
+	suffix := strings.Map(func(r rune) rune {
+		switch r {
+		case '/':
+			return '-'
+		case '{', '}':
+			return -1
+		}
+		return r
+	}, routeTemplate(path))
+
+	if len(suffix) > 100 {
+		suffix = suffix[:100]
+	}
+	return strings.TrimRight(suffix, "-")
+}
+
+func (c *Cassette) replay(req *http.Request, body []byte) (*http.Response, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for i, interaction := range c.interactions {
+		if c.used[i] || !c.matches(req, body, &interaction.Request) {
+			continue
+		}
+
+		c.used[i] = true
+		respBody := interaction.Response.bytes()
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          ioutil.NopCloser(bytes.NewReader(respBody)),
+			ContentLength: int64(len(respBody)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, errors.Errorf("no recorded interaction left for %s %s in cassette %s", req.Method, req.URL, c.dir)
+}
+
+func (c *Cassette) matches(req *http.Request, body []byte, recorded *cassetteRequest) bool {
+	recordedURL, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+
+	m := c.Matching
+	switch {
+	case m.Method && req.Method != recorded.Method:
+		return false
+	case m.Path && req.URL.Path != recordedURL.Path:
+		return false
+	case m.Query && req.URL.Query().Encode() != recordedURL.Query().Encode():
+		return false
+	case m.Accept && mediaTypes(req.Header) != mediaTypes(recorded.Header):
+		return false
+	case m.Body && !bytes.Equal(redactBody(body), recorded.bytes()):
+		return false
+	}
+
+	return true
+}
+
+func mediaTypes(header http.Header) string {
+	var types []string
+	for _, value := range header.Values(HeaderNameAccept) {
+		for _, t := range strings.Split(value, ",") {
+			types = append(types, strings.TrimSpace(t))
+		}
+	}
+	sort.Strings(types)
+	return strings.Join(types, ",")
+}
+
+func (c *Cassette) redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+
+	names := append([]string{HeaderNameAuthorization, HeaderNameCsrfToken, "Cookie", "Set-Cookie"}, c.RedactHeaders...)
+	for _, name := range names {
+		if values := header.Values(name); len(values) > 0 {
+			header.Del(name)
+			for range values {
+				header.Add(name, redacted)
+			}
+		}
+	}
+
+	return header
+}
+
+var (
+	redactedPassword   = regexp.MustCompile(`(j_password=)[^&]*`)
+	redactedJSONSecret = regexp.MustCompile(`("(?:password|bearerToken|token)"\s*:\s*")(?:[^"\\]|\\.)*`)
+)
+
+// redactBody removes the password of form logins, the passwords of users created or updated, the API tokens
+// created and the bearer tokens handed out for them
+func redactBody(body []byte) []byte {
+	body = redactedPassword.ReplaceAll(body, []byte("${1}"+redacted))
+	return redactedJSONSecret.ReplaceAll(body, []byte("${1}"+redacted))
+}
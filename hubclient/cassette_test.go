@@ -0,0 +1,239 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordScanSession records an API token login, a version check and a rapid scan upload into dir
+func recordScanSession(t *testing.T, dir string) (serverURL string, uploads []string) {
+	ts := &tokenServer{expiresInMs: time.Hour.Milliseconds()}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			body, _ := ioutil.ReadAll(r.Body)
+			uploads = append(uploads, string(body))
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		ts.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	cassette, err := NewCassette(dir, CassetteRecord)
+	require.NoError(t, err)
+
+	client, err := New(server.URL,
+		WithHTTPClient(server.Client()),
+		WithCassette(cassette),
+		WithCredentials(ApiTokenCredentials{Token: "secret-api-token"}))
+	require.NoError(t, err)
+
+	_, err = client.CurrentVersion()
+	require.NoError(t, err)
+
+	chunks := NewArrayChunkIterator([]string{`{"chunk": 1}`, `{"chunk": 2}`})
+	require.NoError(t, client.UploadBdioFilesByChunk(server.URL+"/api/developer-scans/1", 2, &chunks))
+
+	return server.URL, uploads
+}
+
+func TestCassetteRecordsRedactedFixtures(t *testing.T) {
+	dir := t.TempDir()
+	_, uploads := recordScanSession(t, dir)
+	assert.Equal(t, []string{`{"chunk": 1}`, `{"chunk": 2}`, ""}, uploads)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 5)
+	assert.Equal(t, "0001-POST-api-tokens-authenticate.json", filepath.Base(files[0]))
+	assert.Equal(t, "0003-PUT-api-developer-scans-id.json", filepath.Base(files[2]))
+
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		for _, secret := range []string{"secret-api-token", "bearer-1", "csrf-1"} {
+			assert.False(t, strings.Contains(string(data), secret), "%s leaked into %s", secret, file)
+		}
+	}
+}
+
+func TestCassetteReplaysWithoutNetwork(t *testing.T) {
+	dir := t.TempDir()
+	serverURL, _ := recordScanSession(t, dir)
+
+	cassette, err := NewCassette(dir, CassetteReplay)
+	require.NoError(t, err)
+	cassette.Matching.Body = true
+
+	// the server is closed, every response has to come from the cassette
+	client, err := New(serverURL, WithCassette(cassette), WithCredentials(ApiTokenCredentials{Token: "another-token"}))
+	require.NoError(t, err)
+
+	version, err := client.CurrentVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "", version.Version)
+
+	chunks := NewArrayChunkIterator([]string{`{"chunk": 1}`, `{"chunk": 2}`})
+	require.NoError(t, client.UploadBdioFilesByChunk(serverURL+"/api/developer-scans/1", 2, &chunks))
+	assert.Equal(t, 0, cassette.Unused())
+
+	_, err = client.CurrentVersion()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no recorded interaction left for GET")
+}
+
+func TestCassetteMatchesUploadedBodies(t *testing.T) {
+	dir := t.TempDir()
+	serverURL, _ := recordScanSession(t, dir)
+
+	cassette, err := NewCassette(dir, CassetteReplay)
+	require.NoError(t, err)
+	cassette.Matching.Body = true
+
+	client, err := New(serverURL, WithCassette(cassette), WithCredentials(ApiTokenCredentials{Token: "another-token"}))
+	require.NoError(t, err)
+
+	chunks := NewArrayChunkIterator([]string{`{"chunk": 1}`, `{"chunk": 3}`})
+	err = client.UploadBdioFilesByChunk(serverURL+"/api/developer-scans/1", 2, &chunks)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no recorded interaction left for PUT")
+}
+
+func TestCassetteMatching(t *testing.T) {
+	cassette := &Cassette{Matching: DefaultCassetteMatching}
+	recorded := &cassetteRequest{
+		Method: http.MethodGet,
+		URL:    "https://blackduck/api/projects?limit=10&q=name:demo",
+		Header: http.Header{HeaderNameAccept: []string{"application/vnd.blackducksoftware.project-detail-4+json"}},
+	}
+
+	request := func(url, accept string) *http.Request {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		req.Header.Set(HeaderNameAccept, accept)
+		return req
+	}
+
+	assert.True(t, cassette.matches(request("https://other/api/projects?q=name:demo&limit=10", "application/vnd.blackducksoftware.project-detail-4+json"), nil, recorded))
+	assert.False(t, cassette.matches(request("https://other/api/projects?limit=20&q=name:demo", "application/vnd.blackducksoftware.project-detail-4+json"), nil, recorded))
+	assert.False(t, cassette.matches(request("https://other/api/projects?limit=10&q=name:demo", "application/json"), nil, recorded))
+
+	cassette.Matching.Accept = false
+	cassette.Matching.Query = false
+	assert.True(t, cassette.matches(request("https://other/api/projects", "application/json"), nil, recorded))
+	assert.False(t, cassette.matches(request("https://other"+hubapi.UsersApi, "application/json"), nil, recorded))
+}
+
+func TestRedactBody(t *testing.T) {
+	assert.Equal(t, "j_username=sysadmin&j_password=REDACTED", string(redactBody([]byte("j_username=sysadmin&j_password=blackduck"))))
+	assert.Equal(t, `{"bearerToken": "REDACTED","expiresInMilliseconds":7200000}`, string(redactBody([]byte(`{"bearerToken": "abc.def","expiresInMilliseconds":7200000}`))))
+	assert.Equal(t, `{"userName":"user","password": "REDACTED","active":true}`, string(redactBody([]byte(`{"userName":"user","password": "pa\"ss","active":true}`))))
+	assert.Equal(t, `{"token":"REDACTED","tokenName":"ci"}`, string(redactBody([]byte(`{"token":"NzU3YjQ4","tokenName":"ci"}`))))
+}
+
+func TestCassetteRedactsUserPasswords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"userName": "user"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	cassette, err := NewCassette(dir, CassetteRecord)
+	require.NoError(t, err)
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithCassette(cassette))
+	require.NoError(t, err)
+
+	_, err = client.CreateUser(&hubapi.UserRequest{UserName: "user", Password: `s3cr"et`})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	data, err := ioutil.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "s3cr")
+	assert.Contains(t, string(data), `\"password\":\"REDACTED\"`)
+}
+
+func TestCassetteRedactsAPITokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "https://blackduck/api/current-user/tokens/1")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"token": "NzU3YjQ4ZjgtZDQ0ZS00"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	cassette, err := NewCassette(dir, CassetteRecord)
+	require.NoError(t, err)
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithCassette(cassette))
+	require.NoError(t, err)
+
+	_, token, err := client.CreateApiToken("ci", "for the pipeline", true)
+	require.NoError(t, err)
+	assert.Equal(t, "NzU3YjQ4ZjgtZDQ0ZS00", token, "only the fixture is redacted")
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	data, err := ioutil.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "NzU3YjQ4")
+	assert.Contains(t, string(data), `\"token\": \"REDACTED\"`)
+}
+
+func TestCassetteLeavesTheCallersClientAlone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version": "2024.1.0"}`))
+	}))
+	defer server.Close()
+
+	cassette, err := NewCassette(t.TempDir(), CassetteRecord)
+	require.NoError(t, err)
+
+	httpClient := server.Client()
+	transport := httpClient.Transport
+	for i := 0; i < 2; i++ {
+		client, err := New(server.URL, WithHTTPClient(httpClient), WithCassette(cassette), WithTimeout(time.Second))
+		require.NoError(t, err)
+
+		_, err = client.CurrentVersion()
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, transport, httpClient.Transport)
+	assert.Zero(t, httpClient.Timeout)
+	assert.Nil(t, httpClient.Jar)
+
+	// a cassette wrapped around itself would recurse forever
+	assert.Equal(t, http.RoundTripper(cassette), cassette.Wrap(cassette))
+	assert.NotEqual(t, http.RoundTripper(cassette), cassette.next)
+}
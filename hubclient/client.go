@@ -17,34 +17,67 @@ package hubclient
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"net/http/cookiejar"
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/blackducksoftware/hub-client-go/hubapi"
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// defaultTokenRefreshMargin is how long before its expiry a bearer token obtained with an API token is refreshed
+const defaultTokenRefreshMargin = time.Minute
+
 // Client will need to support CSRF tokens for session-based auth for Hub 4.1.x (or was it 4.0?)
 type Client struct {
-	httpClient    *http.Client
-	baseURL       string
+	httpClient *http.Client
+	baseURL    string
+	// authLock guards the authentication state below, which may be refreshed while requests are in flight
+	authLock      sync.RWMutex
 	authToken     string
 	useAuthToken  bool
 	haveCsrfToken bool
 	csrfToken     string
-	debugFlags    HubClientDebug
 	// Unix time in seconds at which the authToken expires
 	authTokenExpiryInUnixSec int64
-	userAgent                string
+	// apiToken is used to get a new authToken when the current one expires
+	apiToken           string
+	tokenRefreshMargin time.Duration
+	// sessionRelogin enables logging in again with username and password when the session expires
+	sessionRelogin bool
+	username       string
+	password       string
+	// sessionUser is the user of the session, whether or not it may log in again
+	sessionUser string
+	// sessionGeneration is incremented on every successful login
+	sessionGeneration uint64
+	// refreshLock makes sure only one goroutine refreshes the authToken or session at a time
+	refreshLock sync.Mutex
+	debugFlags  HubClientDebug
+	userAgent   string
+	retryPolicy *RetryPolicy
+	middlewares []Middleware
+	limiters    []*limiter
+
+	cache         Cache
+	cachePolicies []CachePolicy
+
+	tracer  trace.Tracer
+	metrics Metrics
+	logger  Logger
+
+	maxResponseSize int64
+	compression     []RequestCompression
+	negotiation     *negotiation
+	dryRun          *DryRun
 }
 
 func NewWithSession(baseURL string, debugFlags HubClientDebug, timeout time.Duration) (*Client, error) {
@@ -53,27 +86,7 @@ func NewWithSession(baseURL string, debugFlags HubClientDebug, timeout time.Dura
 }
 
 func NewWithClient(baseURL string, debugFlags HubClientDebug, httpClient *http.Client) (*Client, error) {
-
-	if httpClient == nil {
-		httpClient = createHttpClient(time.Minute)
-	}
-
-	if httpClient.Jar == nil {
-		jar, err := cookiejar.New(nil) // Look more at this function
-
-		if err != nil {
-			return nil, AnnotateHubClientError(err, "unable to instantiate cookie jar")
-		}
-
-		httpClient.Jar = jar
-	}
-
-	return &Client{
-		httpClient:   httpClient,
-		baseURL:      baseURL,
-		useAuthToken: false,
-		debugFlags:   debugFlags,
-	}, nil
+	return New(baseURL, WithDebugFlags(debugFlags), WithHTTPClient(httpClient))
 }
 
 func NewWithToken(baseURL string, authToken string, debugFlags HubClientDebug, timeout time.Duration) (*Client, error) {
@@ -82,30 +95,14 @@ func NewWithToken(baseURL string, authToken string, debugFlags HubClientDebug, t
 }
 
 func NewWithTokenAndClient(baseURL string, authToken string, debugFlags HubClientDebug, httpClient *http.Client) (*Client, error) {
-	if httpClient == nil {
-		httpClient = createHttpClient(time.Minute)
-	}
-
-	return &Client{
-		httpClient:   httpClient,
-		baseURL:      baseURL,
-		authToken:    authToken,
-		useAuthToken: true,
-		debugFlags:   debugFlags,
-	}, nil
+	return New(baseURL, WithDebugFlags(debugFlags), WithHTTPClient(httpClient), WithCredentials(BearerTokenCredentials{Token: authToken}))
 }
 
+// createHttpClient creates an http.Client which verifies the server certificate against the system roots.
+// Use NewHTTPClient for anything else.
 func createHttpClient(timeout time.Duration) *http.Client {
-	tr := &http.Transport{
-		Proxy:           http.ProxyFromEnvironment,
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-
-	client := &http.Client{
-		Timeout:   timeout,
-		Transport: tr,
-	}
-
+	// the default options have no files to load, so this cannot fail
+	client, _ := NewHTTPClient(timeout, nil)
 	return client
 }
 
@@ -117,6 +114,55 @@ func (c *Client) SetTimeout(timeout time.Duration) {
 	c.httpClient.Timeout = timeout
 }
 
+// do sends a prepared request through the middlewares and the cache, taking care of re-authentication and retries.
+// Every request made by the Client goes through here.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if d := c.dryRun; d != nil {
+		if resp, recorded, err := d.record(req); recorded {
+			c.log().Info("Dry run, request not sent", methodField(req.Method), urlField(req.URL.String()))
+			return resp, err
+		}
+	}
+
+	if err := c.compressRequest(req); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := c.doTraced(req, c.negotiating(c.chain(c.doCached)))
+	if resp != nil && resp.Body == nil {
+		// middlewares may return responses without a body
+		resp.Body = http.NoBody
+	}
+
+	c.logResponse(req, resp, err, time.Since(start))
+
+	return resp, err
+}
+
+// doAuthenticated sends the request, refreshing expired credentials on the way
+func (c *Client) doAuthenticated(req *http.Request) (*http.Response, error) {
+	if c.apiToken != "" && usesBearerToken(req) {
+		return c.doWithTokenRefresh(req)
+	}
+
+	if c.canRelogin(req) {
+		return c.doWithRelogin(req)
+	}
+
+	return c.send(req)
+}
+
+// send sends the request through the underlying http.Client, retrying it according to the retry policy
+// and throttling it according to the rate limits
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if policy := c.retryPolicy; policy.allows(req) {
+		return c.doWithRetries(req, policy)
+	}
+
+	return c.roundTrip(withAttempt(req, 1))
+}
+
 func readBytes(readCloser io.ReadCloser, expected int64) ([]byte, error) {
 
 	defer readCloser.Close()
@@ -133,7 +179,7 @@ func readBytes(readCloser io.ReadCloser, expected int64) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-func validateHTTPResponse(resp *http.Response, debugFlags HubClientDebug, expectedStatusCodes ...int) error {
+func (c *Client) validateHTTPResponse(resp *http.Response, expectedStatusCodes ...int) error {
 	statusCode := 0
 	if resp != nil {
 		statusCode = resp.StatusCode
@@ -149,8 +195,12 @@ func validateHTTPResponse(resp *http.Response, debugFlags HubClientDebug, expect
 	}
 
 	if !statusCodeExpected {
-		body := readResponseBody(resp, debugFlags)
-		return newHubClientError(body, resp, fmt.Sprintf("got a %d response instead of %d", statusCode, expectedStatusCodes), nil)
+		body := c.readResponseBody(resp)
+		message := fmt.Sprintf("got a %d response instead of %d", statusCode, expectedStatusCodes)
+		if attempts := attemptsOf(resp); attempts > 1 {
+			message = fmt.Sprintf("%s after %d attempts", message, attempts)
+		}
+		return newHubClientError(body, resp, message, nil)
 	}
 
 	return nil
@@ -158,12 +208,13 @@ func validateHTTPResponse(resp *http.Response, debugFlags HubClientDebug, expect
 
 func (c *Client) processResponse(resp *http.Response, result interface{}, expectedStatusCodes ...int) error {
 
-	if err := validateHTTPResponse(resp, c.debugFlags, expectedStatusCodes...); err != nil {
+	if err := c.validateHTTPResponse(resp, expectedStatusCodes...); err != nil {
 		return AnnotateHubClientError(err, "error validating HTTP Response")
 	}
 
 	if result == nil {
-		// Don't have a result to deserialize to, skip it
+		// Don't have a result to deserialize to, skip it, but let the connection and its rate limit slot go
+		discardResponse(resp)
 		return nil
 	}
 
@@ -172,7 +223,7 @@ func (c *Client) processResponse(resp *http.Response, result interface{}, expect
 		return newHubClientError(bodyBytes, resp, fmt.Sprintf("error reading HTTP Response: %+v", err), err)
 	}
 
-	debugReportBytes(bodyBytes, c.debugFlags)
+	c.debugReportBytes(bodyBytes)
 
 	if err := json.Unmarshal(bodyBytes, result); err != nil {
 		return newHubClientError(bodyBytes, resp, fmt.Sprintf("error parsing HTTP Response: %+v", err), err)
@@ -182,29 +233,67 @@ func (c *Client) processResponse(resp *http.Response, result interface{}, expect
 }
 
 func (c *Client) HttpGetString(url string, result *string, expectedStatusCode []int, mimetypes ...string) (error, int) {
-	err, response := c.httpGet(url, nil, expectedStatusCode, mimetypes...)
+	return c.HttpGetStringContext(context.Background(), url, result, expectedStatusCode, mimetypes...)
+}
+
+func (c *Client) HttpGetStringContext(ctx context.Context, url string, result *string, expectedStatusCode []int, mimetypes ...string) (error, int) {
+	err, response := c.httpGetBody(ctx, url, expectedStatusCode, mimetypes...)
 
 	statusCode := getResponseStatus(response)
-	body := readResponseBody(response, c.debugFlags)
+	if err != nil || response == nil {
+		*result = string(c.readResponseBody(response))
+		return err, statusCode
+	}
+
+	body, err := readBytes(response.Body, response.ContentLength)
+	if err != nil {
+		return newHubClientError(body, response, fmt.Sprintf("error reading HTTP Response: %+v", err), err), statusCode
+	}
+
+	c.debugReportBytes(body)
 	*result = string(body)
 
-	return err, statusCode
+	return nil, statusCode
 }
 
 func (c *Client) HttpGetJSON(url string, result interface{}, expectedStatusCode int, mimetypes ...string) error {
-	err, _ := c.httpGet(url, result, []int{expectedStatusCode}, mimetypes...)
+	return c.HttpGetJSONContext(context.Background(), url, result, expectedStatusCode, mimetypes...)
+}
+
+func (c *Client) HttpGetJSONContext(ctx context.Context, url string, result interface{}, expectedStatusCode int, mimetypes ...string) error {
+	err, _ := c.httpGet(ctx, url, result, []int{expectedStatusCode}, mimetypes...)
 	return err
 }
 
-func (c *Client) httpGet(url string, result interface{}, expectedStatusCodes []int, mimetypes ...string) (error, *http.Response) {
+func (c *Client) httpGet(ctx context.Context, url string, result interface{}, expectedStatusCodes []int, mimetypes ...string) (error, *http.Response) {
+	err, resp := c.sendGet(ctx, url, result, mimetypes...)
+	if err != nil {
+		return err, resp
+	}
 
-	var resp *http.Response
+	err = c.processResponse(resp, result, expectedStatusCodes...)
+	return AnnotateHubClientErrorf(err, "unable to process response from GET to %s", url), resp
+}
 
-	if c.debugFlags&HubClientDebugTimings != 0 {
-		log.Debugf("DEBUG HTTP STARTING GET REQUEST: %s", url)
+// httpGetBody is httpGet leaving the body of the response to the caller, who must read and close it
+func (c *Client) httpGetBody(ctx context.Context, url string, expectedStatusCodes []int, mimetypes ...string) (error, *http.Response) {
+	err, resp := c.sendGet(ctx, url, nil, mimetypes...)
+	if err != nil {
+		return err, resp
 	}
 
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	err = c.validateHTTPResponse(resp, expectedStatusCodes...)
+	return AnnotateHubClientErrorf(AnnotateHubClientError(err, "error validating HTTP Response"), "unable to process response from GET to %s", url), resp
+}
+
+// sendGet sends a GET request for url, asking for mimetypes or else the media type of result
+func (c *Client) sendGet(ctx context.Context, url string, result interface{}, mimetypes ...string) (error, *http.Response) {
+
+	var resp *http.Response
+
+	c.debugRequestStart(http.MethodGet, url)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return newHubClientError(nil, nil, fmt.Sprintf("error creating http get request for %s: %+v", url, err), err), resp
 	}
@@ -224,37 +313,46 @@ func (c *Client) httpGet(url string, result interface{}, expectedStatusCodes []i
 	}
 
 	httpStart := time.Now()
-	if resp, err = c.httpClient.Do(req); err != nil {
-		body := readResponseBody(resp, c.debugFlags)
+	if resp, err = c.do(req); err != nil {
+		body := c.readResponseBody(resp)
 		return newHubClientError(body, resp, fmt.Sprintf("error getting HTTP Response from %s: %+v", url, err), err), resp
 	}
 
-	if c.debugFlags&HubClientDebugTimings != 0 {
-		httpElapsed := time.Since(httpStart)
-		log.Debugf("DEBUG HTTP GET ELAPSED TIME: %d ms.   -- Request: %s", (httpElapsed / 1000 / 1000), url)
-	}
+	c.debugRequestElapsed(http.MethodGet, url, httpStart)
 
-	err = c.processResponse(resp, result, expectedStatusCodes...)
-	return AnnotateHubClientErrorf(err, "unable to process response from GET to %s", url), resp
+	return nil, resp
 }
 
 func (c *Client) HttpPutString(url string, data string, contentType string, expectedStatusCode int) error {
-	return c.HttpPutStringWithHeader(url, data, contentType, expectedStatusCode, nil)
+	return c.HttpPutStringWithHeaderContext(context.Background(), url, data, contentType, expectedStatusCode, nil)
+}
+
+func (c *Client) HttpPutStringContext(ctx context.Context, url string, data string, contentType string, expectedStatusCode int) error {
+	return c.HttpPutStringWithHeaderContext(ctx, url, data, contentType, expectedStatusCode, nil)
 }
 
 func (c *Client) HttpPutStringWithHeader(url string, data string, contentType string, expectedStatusCode int, header http.Header) error {
-	if c.debugFlags&HubClientDebugTimings != 0 {
-		log.Debugf("DEBUG HTTP STARTING %s STRING REQUEST: %s", http.MethodPut, url)
-	}
+	return c.HttpPutStringWithHeaderContext(context.Background(), url, data, contentType, expectedStatusCode, header)
+}
+
+func (c *Client) HttpPutStringWithHeaderContext(ctx context.Context, url string, data string, contentType string, expectedStatusCode int, header http.Header) error {
+	c.debugRequestStart(http.MethodPut, url)
 
 	reader := strings.NewReader(data)
-	return c.putRequestWithHeader(url, reader, contentType, expectedStatusCode, header)
+	return c.putRequestWithHeader(ctx, url, reader, contentType, header, expectedStatusCode)
 }
 
 func (c *Client) HttpPutJSON(url string, data interface{}, contentType string, expectedStatusCode int) error {
-	if c.debugFlags&HubClientDebugTimings != 0 {
-		log.Debugf("DEBUG HTTP STARTING %s JSON REQUEST: %s", http.MethodPut, url)
-	}
+	return c.HttpPutJSONContext(context.Background(), url, data, contentType, expectedStatusCode)
+}
+
+func (c *Client) HttpPutJSONContext(ctx context.Context, url string, data interface{}, contentType string, expectedStatusCode int) error {
+	return c.putJSON(ctx, url, data, contentType, nil, expectedStatusCode)
+}
+
+// putJSON is HttpPutJSONContext accepting extra headers and several status codes
+func (c *Client) putJSON(ctx context.Context, url string, data interface{}, contentType string, header http.Header, expectedStatusCodes ...int) error {
+	c.debugRequestStart(http.MethodPut, url)
 
 	// Encode json
 	var buf bytes.Buffer
@@ -264,52 +362,56 @@ func (c *Client) HttpPutJSON(url string, data interface{}, contentType string, e
 	}
 	reader := &buf
 
-	return c.putRequest(url, reader, contentType, expectedStatusCode)
+	return c.putRequestWithHeader(ctx, url, reader, contentType, header, expectedStatusCodes...)
 }
 
-func (c *Client) putRequest(url string, reader io.Reader, contentType string, expectedStatusCode int) error {
-	return c.putRequestWithHeader(url, reader, contentType, expectedStatusCode, nil)
+func (c *Client) putRequest(ctx context.Context, url string, reader io.Reader, contentType string, expectedStatusCode int) error {
+	return c.putRequestWithHeader(ctx, url, reader, contentType, nil, expectedStatusCode)
 }
 
-func (c *Client) putRequestWithHeader(url string, reader io.Reader, contentType string, expectedStatusCode int, header http.Header) error {
-	req, err := http.NewRequest(http.MethodPut, url, reader)
+func (c *Client) putRequestWithHeader(ctx context.Context, url string, reader io.Reader, contentType string, header http.Header, expectedStatusCodes ...int) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, reader)
 	if err != nil {
 		return newHubClientError(nil, nil, fmt.Sprintf("error creating http put request for %s: %+v", url, err), err)
 	}
+	req = withExpectedStatus(req, expectedStatusCodes...)
 
 	req.Header.Set(HeaderNameContentType, contentType)
 
 	c.applyHeaderValues(req, header)
-	log.Debugf("PUT Request: %+v.", maskAuthHeader(req))
+	c.debugRequest(req)
 
 	httpStart := time.Now()
 	var resp *http.Response
-	if resp, err = c.httpClient.Do(req); err != nil {
-		body := readResponseBody(resp, c.debugFlags)
+	if resp, err = c.do(req); err != nil {
+		body := c.readResponseBody(resp)
 		return newHubClientError(body, resp, fmt.Sprintf("error getting HTTP Response from PUT to %s: %+v", url, err), err)
 	}
 
-	if c.debugFlags&HubClientDebugTimings != 0 {
-		httpElapsed := time.Since(httpStart)
-		log.Debugf("DEBUG HTTP PUT ELAPSED TIME: %d ms.   -- Request: %s", (httpElapsed / 1000 / 1000), url)
-	}
+	defer discardResponse(resp)
+
+	c.debugRequestElapsed(http.MethodPut, url, httpStart)
 
-	return AnnotateHubClientErrorf(c.processResponse(resp, nil, expectedStatusCode), "unable to process response from PUT to %s", url) // TODO: Maybe need a response too?
+	return AnnotateHubClientErrorf(c.processResponse(resp, nil, expectedStatusCodes...), "unable to process response from PUT to %s", url) // TODO: Maybe need a response too?
 }
 
 func (c *Client) HttpPostString(url string, data string, contentType string, expectedStatusCode int) (string, error) {
-	if c.debugFlags&HubClientDebugTimings != 0 {
-		log.Debugf("DEBUG HTTP STARTING %s STRING REQUEST: %s", http.MethodPost, url)
-	}
+	return c.HttpPostStringContext(context.Background(), url, data, contentType, expectedStatusCode)
+}
+
+func (c *Client) HttpPostStringContext(ctx context.Context, url string, data string, contentType string, expectedStatusCode int) (string, error) {
+	c.debugRequestStart(http.MethodPost, url)
 
 	reader := strings.NewReader(data)
-	return c.postRequest(url, reader, contentType, expectedStatusCode)
+	return c.postRequest(ctx, url, reader, contentType, expectedStatusCode)
 }
 
 func (c *Client) HttpPostJSON(url string, data interface{}, contentType string, expectedStatusCode int) (string, error) {
-	if c.debugFlags&HubClientDebugTimings != 0 {
-		log.Debugf("DEBUG HTTP STARTING %s JSON REQUEST: %s", http.MethodPost, url)
-	}
+	return c.HttpPostJSONContext(context.Background(), url, data, contentType, expectedStatusCode)
+}
+
+func (c *Client) HttpPostJSONContext(ctx context.Context, url string, data interface{}, contentType string, expectedStatusCode int) (string, error) {
+	c.debugRequestStart(http.MethodPost, url)
 
 	// Encode json
 	var buf bytes.Buffer
@@ -319,76 +421,82 @@ func (c *Client) HttpPostJSON(url string, data interface{}, contentType string,
 	}
 	reader := &buf
 
-	return c.postRequest(url, reader, contentType, expectedStatusCode)
+	return c.postRequest(ctx, url, reader, contentType, expectedStatusCode)
 }
 
 func (c *Client) HttpPostFile(url string, filePath string, contentType string) (string, int, error) {
+	return c.HttpPostFileContext(context.Background(), url, filePath, contentType)
+}
+
+func (c *Client) HttpPostFileContext(ctx context.Context, url string, filePath string, contentType string) (string, int, error) {
 
 	var resp *http.Response
 	var err error
 
-	if c.debugFlags&HubClientDebugTimings != 0 {
-		log.Debugf("DEBUG HTTP STARTING POST REQUEST: %s", url)
-	}
+	c.debugRequestStart(http.MethodPost, url)
 
 	file, err := os.Open(filePath)
 
 	if err != nil {
-		log.Errorf("Error opening file: %+v", err)
-		return "", -1, err
+		c.log().Error("Error opening file", Field{"file", filePath}, errorField(err))
+		return "", -1, AnnotateHubClientErrorf(err, "unable to open %s", filePath)
 	}
 
-	httpStart := time.Now()
-	req, err := http.NewRequest(http.MethodPost, url, file)
-	req.Header.Set(HeaderNameContentType, contentType)
-
-	c.setAuthHeaders(req)
+	defer file.Close()
 
+	httpStart := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, file)
 	if err != nil {
-		log.Errorf("Error making http post request: %+v.", err)
-		return "", -1, err
+		c.log().Error("Error making http post request", urlField(url), errorField(err))
+		return "", -1, newHubClientError(nil, nil, fmt.Sprintf("error creating http post request for %s: %+v", url, err), err)
 	}
 
-	log.Debugf("POST Request: %+v.", maskAuthHeader(req))
-
-	if resp, err = c.httpClient.Do(req); err != nil {
-		log.Errorf("Error getting HTTP Response: %+v.", err)
-		readResponseBody(resp, c.debugFlags)
-		return "", resp.StatusCode, err
+	if info, err := file.Stat(); err == nil {
+		req.ContentLength = info.Size()
 	}
 
-	httpElapsed := time.Since(httpStart)
+	req.Header.Set(HeaderNameContentType, contentType)
+
+	c.applyHeaderValues(req, nil)
+
+	c.debugRequest(req)
 
-	if c.debugFlags&HubClientDebugTimings != 0 {
-		log.Debugf("DEBUG HTTP POST ELAPSED TIME: %d ms.   -- Request: %s", (httpElapsed / 1000 / 1000), url)
+	if resp, err = c.do(req); err != nil {
+		c.log().Error("Error getting HTTP Response", methodField(http.MethodPost), urlField(url), errorField(err))
+		body := c.readResponseBody(resp)
+		return "", getResponseStatus(resp), newHubClientError(body, resp, fmt.Sprintf("error getting HTTP Response from POST to %s: %+v", url, err), err)
 	}
+	defer resp.Body.Close()
+
+	c.debugRequestElapsed(http.MethodPost, url, httpStart)
+
 	return resp.Header.Get("Location"), resp.StatusCode, nil
 }
 
-func (c *Client) postRequest(url string, reader io.Reader, contentType string, expectedStatusCode int) (string, error) {
-	req, err := http.NewRequest(http.MethodPost, url, reader)
+func (c *Client) postRequest(ctx context.Context, url string, reader io.Reader, contentType string, expectedStatusCode int) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, reader)
 	if err != nil {
 		return "", newHubClientError(nil, nil, fmt.Sprintf("error creating http post request for %s: %+v", url, err), err)
 	}
+	req = withExpectedStatus(req, expectedStatusCode)
 
 	req.Header.Set(HeaderNameContentType, contentType)
 
 	c.applyHeaderValues(req, nil)
 
-	log.Debugf("POST Request: %+v.", maskAuthHeader(req))
+	c.debugRequest(req)
 
 	httpStart := time.Now()
 
 	var resp *http.Response
-	if resp, err = c.httpClient.Do(req); err != nil {
-		body := readResponseBody(resp, c.debugFlags)
+	if resp, err = c.do(req); err != nil {
+		body := c.readResponseBody(resp)
 		return "", newHubClientError(body, resp, fmt.Sprintf("error getting HTTP Response from POST to %s: %+v", url, err), err)
 	}
 
-	if c.debugFlags&HubClientDebugTimings != 0 {
-		httpElapsed := time.Since(httpStart)
-		log.Debugf("DEBUG HTTP POST ELAPSED TIME: %d ms. -- Request: %s", (httpElapsed / 1000 / 1000), url)
-	}
+	defer discardResponse(resp)
+
+	c.debugRequestElapsed(http.MethodPost, url, httpStart)
 
 	if err := c.processResponse(resp, nil, expectedStatusCode); err != nil {
 		return "", AnnotateHubClientErrorf(err, "unable to process response from POST to %s", url)
@@ -398,12 +506,14 @@ func (c *Client) postRequest(url string, reader io.Reader, contentType string, e
 }
 
 func (c *Client) HttpPostJSONExpectResult(url string, data interface{}, result interface{}, contentType string, expectedStatusCode int) (string, error) {
+	return c.HttpPostJSONExpectResultContext(context.Background(), url, data, result, contentType, expectedStatusCode)
+}
+
+func (c *Client) HttpPostJSONExpectResultContext(ctx context.Context, url string, data interface{}, result interface{}, contentType string, expectedStatusCode int) (string, error) {
 
 	var resp *http.Response
 
-	if c.debugFlags&HubClientDebugTimings != 0 {
-		log.Debugf("DEBUG HTTP STARTING POST REQUEST: %s", url)
-	}
+	c.debugRequestStart(http.MethodPost, url)
 
 	// Encode json
 	var buf bytes.Buffer
@@ -413,27 +523,25 @@ func (c *Client) HttpPostJSONExpectResult(url string, data interface{}, result i
 		return "", newHubClientError(nil, nil, fmt.Sprintf("error encoding json: %+v", err), err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, url, &buf)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &buf)
 	if err != nil {
 		return "", newHubClientError(nil, nil, fmt.Sprintf("error creating http post request for %s: %+v", url, err), err)
 	}
+	req = withExpectedStatus(req, expectedStatusCode)
 
 	req.Header.Set(HeaderNameContentType, contentType)
 
 	c.applyHeaderValues(req, nil)
 
-	log.Debugf("POST Request: %+v.", maskAuthHeader(req))
+	c.debugRequest(req)
 
 	httpStart := time.Now()
-	if resp, err = c.httpClient.Do(req); err != nil {
-		body := readResponseBody(resp, c.debugFlags)
+	if resp, err = c.do(req); err != nil {
+		body := c.readResponseBody(resp)
 		return "", newHubClientError(body, resp, fmt.Sprintf("error getting HTTP Response from POST to %s: %+v", url, err), err)
 	}
 
-	if c.debugFlags&HubClientDebugTimings != 0 {
-		httpElapsed := time.Since(httpStart)
-		log.Debugf("DEBUG HTTP POST ELAPSED TIME: %d ms.   -- Request: %s", (httpElapsed / 1000 / 1000), url)
-	}
+	c.debugRequestElapsed(http.MethodPost, url, httpStart)
 
 	if err := c.processResponse(resp, result, expectedStatusCode); err != nil {
 		return "", AnnotateHubClientErrorf(err, "unable to process response from POST to %s", url)
@@ -444,33 +552,34 @@ func (c *Client) HttpPostJSONExpectResult(url string, data interface{}, result i
 
 // HttpPostRawJSONExpectResult http post request without encoding the data
 func (c *Client) HttpPostRawJSONExpectResult(url string, data io.Reader, result interface{}, contentType string, expectedStatusCode int) (string, error) {
+	return c.HttpPostRawJSONExpectResultContext(context.Background(), url, data, result, contentType, expectedStatusCode)
+}
+
+// HttpPostRawJSONExpectResultContext is HttpPostRawJSONExpectResult bound to the provided context
+func (c *Client) HttpPostRawJSONExpectResultContext(ctx context.Context, url string, data io.Reader, result interface{}, contentType string, expectedStatusCode int) (string, error) {
 	var resp *http.Response
 
-	if c.debugFlags&HubClientDebugTimings != 0 {
-		log.Debugf("DEBUG HTTP STARTING POST REQUEST: %s", url)
-	}
+	c.debugRequestStart(http.MethodPost, url)
 
-	req, err := http.NewRequest(http.MethodPost, url, data)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, data)
 	if err != nil {
 		return "", newHubClientError(nil, nil, fmt.Sprintf("error creating http post request for %s: %+v", url, err), err)
 	}
+	req = withExpectedStatus(req, expectedStatusCode)
 
 	req.Header.Set(HeaderNameContentType, contentType)
 
 	c.applyHeaderValues(req, nil)
 
-	log.Debugf("POST Request: %+v.", maskAuthHeader(req))
+	c.debugRequest(req)
 
 	httpStart := time.Now()
-	if resp, err = c.httpClient.Do(req); err != nil {
-		body := readResponseBody(resp, c.debugFlags)
+	if resp, err = c.do(req); err != nil {
+		body := c.readResponseBody(resp)
 		return "", newHubClientError(body, resp, fmt.Sprintf("error getting HTTP Response from POST to %s: %+v", url, err), err)
 	}
 
-	if c.debugFlags&HubClientDebugTimings != 0 {
-		httpElapsed := time.Since(httpStart)
-		log.Debugf("DEBUG HTTP POST ELAPSED TIME: %d ms.   -- Request: %s", (httpElapsed / 1000 / 1000), url)
-	}
+	c.debugRequestElapsed(http.MethodPost, url, httpStart)
 
 	if err := c.processResponse(resp, result, expectedStatusCode); err != nil {
 		return "", AnnotateHubClientErrorf(err, "unable to process response from POST to %s", url)
@@ -480,36 +589,38 @@ func (c *Client) HttpPostRawJSONExpectResult(url string, data io.Reader, result
 }
 
 func (c *Client) HttpDelete(url string, contentType string, expectedStatusCode int) error {
+	return c.HttpDeleteContext(context.Background(), url, contentType, expectedStatusCode)
+}
+
+func (c *Client) HttpDeleteContext(ctx context.Context, url string, contentType string, expectedStatusCode int) error {
 
 	var resp *http.Response
 	var err error
 
-	if c.debugFlags&HubClientDebugTimings != 0 {
-		log.Debugf("DEBUG HTTP STARTING DELETE REQUEST: %s", url)
-	}
+	c.debugRequestStart(http.MethodDelete, url)
 
-	req, err := http.NewRequest(http.MethodDelete, url, bytes.NewBuffer([]byte{}))
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, bytes.NewBuffer([]byte{}))
 	if err != nil {
 		return newHubClientError(nil, nil, fmt.Sprintf("error creating http delete request for %s: %+v", url, err), err)
 	}
+	req = withExpectedStatus(req, expectedStatusCode)
 
 	req.Header.Set(HeaderNameContentType, contentType)
 
 	c.applyHeaderValues(req, nil)
 
-	log.Debugf("DELETE Request: %+v.", maskAuthHeader(req))
+	c.debugRequest(req)
 
 	httpStart := time.Now()
 
-	if resp, err = c.httpClient.Do(req); err != nil {
-		body := readResponseBody(resp, c.debugFlags)
+	if resp, err = c.do(req); err != nil {
+		body := c.readResponseBody(resp)
 		return newHubClientError(body, resp, fmt.Sprintf("error getting HTTP Response from DELETE to %s: %+v", url, err), err)
 	}
 
-	if c.debugFlags&HubClientDebugTimings != 0 {
-		httpElapsed := time.Since(httpStart)
-		log.Debugf("DEBUG HTTP DELETE ELAPSED TIME: %d ms.   -- Request: %s", (httpElapsed / 1000 / 1000), url)
-	}
+	defer discardResponse(resp)
+
+	c.debugRequestElapsed(http.MethodDelete, url, httpStart)
 
 	return AnnotateHubClientErrorf(c.processResponse(resp, nil, expectedStatusCode), "unable to process response from DELETE to %s", url)
 }
@@ -534,6 +645,8 @@ func (c *Client) applyHeaderValues(request *http.Request, header http.Header) {
 }
 
 func (c *Client) setAuthHeaders(request *http.Request) {
+	c.authLock.RLock()
+	defer c.authLock.RUnlock()
 
 	if c.useAuthToken {
 		request.Header.Set(HeaderNameAuthorization, fmt.Sprintf("Bearer %s", c.authToken))
@@ -545,8 +658,12 @@ func (c *Client) setAuthHeaders(request *http.Request) {
 }
 
 func (c *Client) Count(link string) (int, error) {
+	return c.CountContext(context.Background(), link)
+}
+
+func (c *Client) CountContext(ctx context.Context, link string) (int, error) {
 	var list hubapi.ItemsListBase
-	err := c.HttpGetJSON(link+"?offset=0&limit=1", &list, 200)
+	err := c.HttpGetJSONContext(ctx, link+"?offset=0&limit=1", &list, 200)
 
 	if err != nil {
 		return 0, AnnotateHubClientError(err, "Error trying to retrieve count")
@@ -556,11 +673,29 @@ func (c *Client) Count(link string) (int, error) {
 }
 
 func (c *Client) ForEachPage(link string, listOptions *hubapi.GetListOptions, list interface{}, pageFunc func() error) (err error) {
+	return c.ForEachPageContext(context.Background(), link, listOptions, list, pageFunc)
+}
+
+// ForEachPageContext fetches every page of the list at link into list and calls pageFunc after each one.
+// Paging stops as soon as ctx is done, a page fails to load or pageFunc returns an error.
+// See Pager for a typed alternative.
+func (c *Client) ForEachPageContext(ctx context.Context, link string, listOptions *hubapi.GetListOptions, list interface{}, pageFunc func() error) (err error) {
+	ctx, span := c.startSpan(ctx, "ForEachPage", attribute.String("url.full", link))
+	defer func() { endSpan(span, err) }()
+
+	if v := reflect.ValueOf(list); v.Kind() != reflect.Ptr || v.IsNil() {
+		return HubClientErrorf("list must be a non-nil pointer, not %T", list)
+	}
+
 	listOptions = hubapi.EnsureLimits(listOptions)
 
 	for totalCount := 1; err == nil && *listOptions.Offset < totalCount; listOptions.NextPage() {
+		if err = ctx.Err(); err != nil {
+			return AnnotateHubClientErrorf(err, "paging of %s was interrupted", link)
+		}
+
 		resetList(list)
-		err = c.GetPage(link, listOptions, list)
+		err = c.GetPageContext(ctx, link, listOptions, list)
 		if err == nil {
 			err = pageFunc()
 		}
@@ -579,9 +714,13 @@ func resetList(v interface{}) {
 }
 
 func (c *Client) GetPage(link string, options *hubapi.GetListOptions, list interface{}) error {
+	return c.GetPageContext(context.Background(), link, options, list)
+}
+
+func (c *Client) GetPageContext(ctx context.Context, link string, options *hubapi.GetListOptions, list interface{}) error {
 	listUrl := link + hubapi.ParameterString(options)
 
-	err := c.HttpGetJSON(listUrl, list, 200)
+	err := c.HttpGetJSONContext(ctx, listUrl, list, 200)
 
 	if err != nil {
 		return AnnotateHubClientError(err, fmt.Sprintf("Error trying to retrieve list %T", list))
@@ -599,19 +738,19 @@ func getResponseStatus(resp *http.Response) int {
 	return statusCode
 }
 
-func readResponseBody(resp *http.Response, debugFlags HubClientDebug) (bodyBytes []byte) {
+func (c *Client) readResponseBody(resp *http.Response) (bodyBytes []byte) {
 
 	if resp == nil {
-		log.Errorf("Empty HTTP Response")
+		c.log().Error("Empty HTTP Response")
 		return nil
 	}
 
 	var err error
 	if bodyBytes, err = readBytes(resp.Body, resp.ContentLength); err != nil {
-		log.Errorf("Error reading HTTP Response: %+v.", err)
+		c.log().Error("Error reading HTTP Response", statusField(resp.StatusCode), errorField(err))
 	}
 
-	debugReportBytes(bodyBytes, debugFlags)
+	c.debugReportBytes(bodyBytes)
 
 	return bodyBytes
 }
@@ -623,6 +762,8 @@ func newHubClientError(respBody []byte, resp *http.Response, message string, und
 	var statusCode int
 	if resp != nil {
 		statusCode = resp.StatusCode
+	} else if underlying, ok := underlyingErr.(*HubClientError); ok {
+		statusCode = underlying.StatusCode
 	}
 
 	var err error
@@ -635,7 +776,12 @@ func newHubClientError(respBody []byte, resp *http.Response, message string, und
 		}
 	}
 
-	hce := &HubClientError{err, statusCode, hre}
+	attempts := attemptsOf(resp)
+	if underlying, ok := underlyingErr.(*HubClientError); ok && underlying.Attempts > attempts {
+		attempts = underlying.Attempts
+	}
+
+	hce := &HubClientError{Err: err, StatusCode: statusCode, HubError: hre, Attempts: attempts}
 	if len(respBody) > 0 {
 		if err := json.Unmarshal(respBody, &hre); err != nil {
 			hce = AnnotateHubClientError(hce, fmt.Sprintf("error unmarshaling HTTP response body: %+v", err)).(*HubClientError)
@@ -647,6 +793,8 @@ func newHubClientError(respBody []byte, resp *http.Response, message string, und
 }
 
 func (c *Client) SetBearerToken(token string) {
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
 	c.authToken = token
 	c.useAuthToken = true
 }
@@ -654,7 +802,14 @@ func (c *Client) SetBearerToken(token string) {
 // GetAuthTokenExpiryTime returns the unix time in seconds at which the cached auth token is set to expire
 // This func returns -1 if the Client is not configured to use auth token
 func (c *Client) GetAuthTokenExpiryTime() int64 {
-	if c == nil || !c.useAuthToken || c.authToken == "" {
+	if c == nil {
+		return -1
+	}
+
+	c.authLock.RLock()
+	defer c.authLock.RUnlock()
+
+	if !c.useAuthToken || c.authToken == "" {
 		return -1
 	}
 	return c.authTokenExpiryInUnixSec
@@ -667,7 +822,7 @@ func (c *Client) SetUserAgent(agent string) {
 
 func maskAuthHeader(req *http.Request) *http.Request {
 	// Deep clone the request
-	reqClone := req.Clone(context.TODO())
+	reqClone := req.Clone(req.Context())
 	if val := reqClone.Header.Get(HeaderNameAuthorization); val != "" {
 		reqClone.Header.Set(HeaderNameAuthorization, fmt.Sprintf("Bearer %s", "<NOT_SHOWN>"))
 	}
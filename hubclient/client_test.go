@@ -15,12 +15,17 @@
 package hubclient
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/blackducksoftware/hub-client-go/hubapi"
 	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestFetchPolicyStatus is a very brittle test because it requires:
@@ -74,3 +79,56 @@ func TestCreateAndDeleteProject(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestClient_HttpGetJSONContextCancelled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewWithClient(server.URL, 0, server.Client())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var version hubapi.CurrentVersion
+	err = client.HttpGetJSONContext(ctx, server.URL+hubapi.CurrentVersionApi, &version, http.StatusOK)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+}
+
+func TestClient_ForEachPageContextStopsWhenCancelled(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set(HeaderNameContentType, "application/json")
+		fmt.Fprint(w, `{"totalCount": 300, "items": [{"name": "project"}]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewWithClient(server.URL, 0, server.Client())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var list hubapi.ProjectList
+	pages := 0
+	err = client.ForEachPageContext(ctx, server.URL+hubapi.ProjectsApi, nil, &list, func() error {
+		pages++
+		cancel()
+		return nil
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), context.Canceled.Error())
+	assert.Equal(t, 1, pages)
+	assert.Equal(t, 1, requests)
+}
@@ -15,14 +15,19 @@
 package hubclient
 
 import (
+	"context"
 	"github.com/blackducksoftware/hub-client-go/hubapi"
 )
 
 func (c *Client) ListAllCodeLocations(options *hubapi.GetListOptions) (*hubapi.CodeLocationList, error) {
+	return c.ListAllCodeLocationsContext(context.Background(), options)
+}
+
+func (c *Client) ListAllCodeLocationsContext(ctx context.Context, options *hubapi.GetListOptions) (*hubapi.CodeLocationList, error) {
 	codeLocationsURL := hubapi.BuildUrl(c.baseURL, hubapi.CodeLocationsApi)
 
 	var codeLocationsList hubapi.CodeLocationList
-	err := c.GetPage(codeLocationsURL, options, &codeLocationsList)
+	err := c.GetPageContext(ctx, codeLocationsURL, options, &codeLocationsList)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve code locations list")
@@ -32,9 +37,13 @@ func (c *Client) ListAllCodeLocations(options *hubapi.GetListOptions) (*hubapi.C
 }
 
 func (c *Client) ListCodeLocations(link hubapi.ResourceLink, options *hubapi.GetListOptions) (*hubapi.CodeLocationList, error) {
+	return c.ListCodeLocationsContext(context.Background(), link, options)
+}
+
+func (c *Client) ListCodeLocationsContext(ctx context.Context, link hubapi.ResourceLink, options *hubapi.GetListOptions) (*hubapi.CodeLocationList, error) {
 
 	var codeLocationList hubapi.CodeLocationList
-	err := c.GetPage(link.Href, options, &codeLocationList)
+	err := c.GetPageContext(ctx, link.Href, options, &codeLocationList)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve code location list")
@@ -44,9 +53,13 @@ func (c *Client) ListCodeLocations(link hubapi.ResourceLink, options *hubapi.Get
 }
 
 func (c *Client) GetCodeLocation(link hubapi.ResourceLink) (*hubapi.CodeLocation, error) {
+	return c.GetCodeLocationContext(context.Background(), link)
+}
+
+func (c *Client) GetCodeLocationContext(ctx context.Context, link hubapi.ResourceLink) (*hubapi.CodeLocation, error) {
 
 	var codeLocation hubapi.CodeLocation
-	err := c.HttpGetJSON(link.Href, &codeLocation, 200)
+	err := c.HttpGetJSONContext(ctx, link.Href, &codeLocation, 200)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve a code location")
@@ -58,13 +71,21 @@ func (c *Client) GetCodeLocation(link hubapi.ResourceLink) (*hubapi.CodeLocation
 // DeleteCodeLocation deletes a code location using
 // https://<base_hub_URL>/api.html#!/composite45code45location45rest45server/deleteCodeLocationUsingDELETE
 func (c *Client) DeleteCodeLocation(codeLocationURL string) error {
-	return c.HttpDelete(codeLocationURL, "application/json", 204)
+	return c.DeleteCodeLocationContext(context.Background(), codeLocationURL)
+}
+
+func (c *Client) DeleteCodeLocationContext(ctx context.Context, codeLocationURL string) error {
+	return c.HttpDeleteContext(ctx, codeLocationURL, "application/json", 204)
 }
 
 func (c *Client) ListScanSummaries(link hubapi.ResourceLink) (*hubapi.ScanSummaryList, error) {
+	return c.ListScanSummariesContext(context.Background(), link)
+}
+
+func (c *Client) ListScanSummariesContext(ctx context.Context, link hubapi.ResourceLink) (*hubapi.ScanSummaryList, error) {
 	// TODO: Need offset/limit
 	var scanSummaryList hubapi.ScanSummaryList
-	err := c.GetPage(link.Href, nil, &scanSummaryList)
+	err := c.GetPageContext(ctx, link.Href, nil, &scanSummaryList)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve scan summary list")
@@ -74,9 +95,13 @@ func (c *Client) ListScanSummaries(link hubapi.ResourceLink) (*hubapi.ScanSummar
 }
 
 func (c *Client) GetScanSummary(link hubapi.ResourceLink) (*hubapi.ScanSummary, error) {
+	return c.GetScanSummaryContext(context.Background(), link)
+}
+
+func (c *Client) GetScanSummaryContext(ctx context.Context, link hubapi.ResourceLink) (*hubapi.ScanSummary, error) {
 
 	var scanSummary hubapi.ScanSummary
-	err := c.HttpGetJSON(link.Href, &scanSummary, 200)
+	err := c.HttpGetJSONContext(ctx, link.Href, &scanSummary, 200)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve a scan summary")
@@ -15,93 +15,121 @@
 package hubclient
 
 import (
+	"context"
 	"errors"
 
 	"github.com/blackducksoftware/hub-client-go/hubapi"
-	log "github.com/sirupsen/logrus"
 )
 
 func (c *Client) ListComponents(options *hubapi.GetListOptions) (*hubapi.ComponentList, error) {
+	return c.ListComponentsContext(context.Background(), options)
+}
+
+func (c *Client) ListComponentsContext(ctx context.Context, options *hubapi.GetListOptions) (*hubapi.ComponentList, error) {
 	componentURL := hubapi.BuildUrl(c.baseURL, hubapi.ComponentsApi)
 
 	var componentList hubapi.ComponentList
-	err := c.GetPage(componentURL, options, &componentList)
+	err := c.GetPageContext(ctx, componentURL, options, &componentList)
 
 	if err != nil {
-		log.Errorf("Error trying to retrieve component list: %+v.", err)
-		return nil, err
+		c.log().Error("Error trying to retrieve component list", urlField(componentURL), errorField(err))
+		return nil, AnnotateHubClientError(err, "Error trying to retrieve component list")
 	}
 
 	return &componentList, nil
 }
 
 func (c *Client) ListAllComponents(options *hubapi.GetListOptions) (*hubapi.ComponentList, error) {
+	return c.ListAllComponentsContext(context.Background(), options)
+}
+
+func (c *Client) ListAllComponentsContext(ctx context.Context, options *hubapi.GetListOptions) (*hubapi.ComponentList, error) {
 	componentURL := hubapi.BuildUrl(c.baseURL, hubapi.ComponentsApi)
 
 	accum := &hubapi.ComponentList{}
 	componentList := hubapi.ComponentList{}
-	err := c.ForEachPage(componentURL, options, &componentList, func() error {
+	err := c.ForEachPageContext(ctx, componentURL, options, &componentList, func() error {
 		accum.ItemsListBase = componentList.ItemsListBase
 		accum.Items = append(accum.Items, componentList.Items...)
 		return nil
 	})
 
 	if err != nil {
-		log.Errorf("Error trying to retrieve component list: %+v.", err)
-		return nil, err
+		c.log().Error("Error trying to retrieve component list", urlField(componentURL), errorField(err))
+		return nil, AnnotateHubClientError(err, "Error trying to retrieve component list")
 	}
 
 	return accum, nil
 }
 
 func (c *Client) GetComponent(link hubapi.ResourceLink) (*hubapi.Component, error) {
+	return c.GetComponentContext(context.Background(), link)
+}
+
+func (c *Client) GetComponentContext(ctx context.Context, link hubapi.ResourceLink) (*hubapi.Component, error) {
 	var component hubapi.Component
-	err := c.HttpGetJSON(link.Href, &component, 200)
+	err := c.HttpGetJSONContext(ctx, link.Href, &component, 200)
 
 	if err != nil {
-		log.Errorf("Error trying to retrieve a component: %+v.", err)
-		return nil, err
+		c.log().Error("Error trying to retrieve a component", urlField(link.Href), errorField(err))
+		return nil, AnnotateHubClientError(err, "Error trying to retrieve a component")
 	}
 
 	return &component, nil
 }
 
 func (c *Client) CreateComponent(componentRequest *hubapi.ComponentRequest) (string, error) {
+	return c.CreateComponentContext(context.Background(), componentRequest)
+}
+
+func (c *Client) CreateComponentContext(ctx context.Context, componentRequest *hubapi.ComponentRequest) (string, error) {
 	componentURL := hubapi.BuildUrl(c.baseURL, hubapi.ComponentsApi)
-	location, err := c.HttpPostJSON(componentURL, componentRequest, "application/json", 201)
+	location, err := c.HttpPostJSONContext(ctx, componentURL, componentRequest, "application/json", 201)
 
 	if err != nil {
-		return location, err
+		return location, TraceHubClientError(err)
 	}
 
 	if location == "" {
-		log.Warnf("Did not get a location header back for component creation")
+		c.log().Warn("Did not get a location header back for component creation")
 	}
 
 	return location, err
 }
 
 func (c *Client) DeleteComponent(componentURL string) error {
-	return c.HttpDelete(componentURL, "application/json", 204)
+	return c.DeleteComponentContext(context.Background(), componentURL)
+}
+
+func (c *Client) DeleteComponentContext(ctx context.Context, componentURL string) error {
+	return c.HttpDeleteContext(ctx, componentURL, "application/json", 204)
 }
 
 func (c *Client) GetComponentVersion(link hubapi.ResourceLink) (*hubapi.ComponentVersion, error) {
+	return c.GetComponentVersionContext(context.Background(), link)
+}
+
+func (c *Client) GetComponentVersionContext(ctx context.Context, link hubapi.ResourceLink) (*hubapi.ComponentVersion, error) {
 	var componentVersion hubapi.ComponentVersion
-	err := c.HttpGetJSON(link.Href, &componentVersion, 200)
+	err := c.HttpGetJSONContext(ctx, link.Href, &componentVersion, 200)
 
 	if err != nil {
-		log.Errorf("Error trying to retrieve a component: %+v.", err)
-		return nil, err
+		c.log().Error("Error trying to retrieve a component", urlField(link.Href), errorField(err))
+		return nil, AnnotateHubClientError(err, "Error trying to retrieve a component")
 	}
 
 	return &componentVersion, nil
 }
 
 func (c *Client) GetComponentVersionRemediation(componentVersionHref string) (*hubapi.ComponentRemediation, error) {
+	return c.GetComponentVersionRemediationContext(context.Background(), componentVersionHref)
+}
+
+func (c *Client) GetComponentVersionRemediationContext(ctx context.Context, componentVersionHref string) (*hubapi.ComponentRemediation, error) {
 	var componentRemediation hubapi.ComponentRemediation
 
 	apiUrl := hubapi.BuildUrl(componentVersionHref, hubapi.RemediatingApi)
-	err := c.HttpGetJSON(apiUrl, &componentRemediation, 200)
+	err := c.HttpGetJSONContext(ctx, apiUrl, &componentRemediation, 200)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve component remediation advice")
@@ -111,15 +139,23 @@ func (c *Client) GetComponentVersionRemediation(componentVersionHref string) (*h
 }
 
 func (c *Client) GetUpgradeGuidanceForComponent(componentVariant hubapi.ComponentVariant) (*hubapi.ComponentUpgradeGuidance, error) {
-	version, err := c.GetComponentVersion(hubapi.ResourceLink{Href: componentVariant.Variant})
+	return c.GetUpgradeGuidanceForComponentContext(context.Background(), componentVariant)
+}
+
+func (c *Client) GetUpgradeGuidanceForComponentContext(ctx context.Context, componentVariant hubapi.ComponentVariant) (*hubapi.ComponentUpgradeGuidance, error) {
+	version, err := c.GetComponentVersionContext(ctx, hubapi.ResourceLink{Href: componentVariant.Variant})
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve component version for upgrade guidance")
 	}
 
-	return c.GetUpgradeGuidanceForComponentVersion(version)
+	return c.GetUpgradeGuidanceForComponentVersionContext(ctx, version)
 }
 
 func (c *Client) GetUpgradeGuidanceForComponentVersion(componentVersion *hubapi.ComponentVersion) (*hubapi.ComponentUpgradeGuidance, error) {
+	return c.GetUpgradeGuidanceForComponentVersionContext(context.Background(), componentVersion)
+}
+
+func (c *Client) GetUpgradeGuidanceForComponentVersionContext(ctx context.Context, componentVersion *hubapi.ComponentVersion) (*hubapi.ComponentUpgradeGuidance, error) {
 	var upgradeGuidance hubapi.ComponentUpgradeGuidance
 
 	if componentVersion == nil {
@@ -127,7 +163,7 @@ func (c *Client) GetUpgradeGuidanceForComponentVersion(componentVersion *hubapi.
 	}
 
 	apiUrl := hubapi.BuildUrl(componentVersion.Meta.Href, hubapi.UpgradeGuidanceApi)
-	err := c.HttpGetJSON(apiUrl, &upgradeGuidance, 200)
+	err := c.HttpGetJSONContext(ctx, apiUrl, &upgradeGuidance, 200)
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve component upgrade guidance")
 	}
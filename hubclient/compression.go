@@ -0,0 +1,175 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const encodingGzip = "gzip"
+
+// RequestCompression makes the Client gzip the bodies of the requests matching its Method and PathPrefix,
+// sending them with Content-Encoding: gzip. Only endpoints of the server which accept compressed bodies,
+// such as the upload of scans, should be matched. The first RequestCompression matching a request applies.
+type RequestCompression struct {
+	// Method is the HTTP method of the requests compressed, all methods if empty
+	Method string
+	// PathPrefix is the beginning of the URL path of the requests compressed, such as /api/developer-scans,
+	// all paths if empty. It is matched on whole path segments.
+	PathPrefix string
+	// MinSize is the size in bytes from which bodies are compressed, smaller ones are sent as they are.
+	// Bodies of unknown size are always compressed.
+	MinSize int64
+}
+
+// SetRequestCompression replaces the rules for compressing request bodies. Without rules bodies are sent
+// uncompressed. Responses are decompressed regardless, the Client always accepts gzip encoded responses.
+// SetRequestCompression must not be called while requests are in flight.
+func (c *Client) SetRequestCompression(rules ...RequestCompression) {
+	c.compression = rules
+}
+
+// WithRequestCompression sets the rules for compressing request bodies, see SetRequestCompression
+func WithRequestCompression(rules ...RequestCompression) Option {
+	return func(o *clientOptions) error {
+		o.compression = append(o.compression, rules...)
+		return nil
+	}
+}
+
+func (r *RequestCompression) matches(req *http.Request) bool {
+	return (r.Method == "" || r.Method == req.Method) && hasPathPrefix(req.URL.Path, r.PathPrefix)
+}
+
+// compressRequest gzips the body of req if a RequestCompression applies to it. The compressed body is held
+// in memory, so it can be sent again when the request is retried.
+func (c *Client) compressRequest(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get(HeaderNameContentEncoding) != "" {
+		return nil
+	}
+
+	var rule *RequestCompression
+	for i := range c.compression {
+		if c.compression[i].matches(req) {
+			rule = &c.compression[i]
+			break
+		}
+	}
+
+	if rule == nil || (req.ContentLength > 0 && req.ContentLength < rule.MinSize) {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := io.Copy(writer, req.Body)
+	req.Body.Close()
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		return errors.WithMessage(err, "unable to compress request body")
+	}
+
+	compressed := buf.Bytes()
+	req.Body = ioutil.NopCloser(bytes.NewReader(compressed))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	req.ContentLength = int64(len(compressed))
+	req.Header.Set(HeaderNameContentEncoding, encodingGzip)
+
+	return nil
+}
+
+// acceptCompression asks for a gzip encoded response, unless the request asks for an encoding already.
+// Setting the header stops http.Transport from decompressing the response itself, decompressResponse
+// takes care of it instead, so compression works the same with any transport.
+func acceptCompression(req *http.Request) {
+	if req.Header.Get(HeaderNameAcceptEncoding) == "" {
+		req.Header.Set(HeaderNameAcceptEncoding, encodingGzip)
+	}
+}
+
+// decompressResponse makes the body of a gzip encoded response read decompressed
+func decompressResponse(resp *http.Response) *http.Response {
+	if resp == nil || resp.Body == nil || !strings.EqualFold(resp.Header.Get(HeaderNameContentEncoding), encodingGzip) {
+		return resp
+	}
+
+	resp.Body = &gzipReadCloser{body: resp.Body}
+	resp.Header.Del(HeaderNameContentEncoding)
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return resp
+}
+
+// gzipReadCloser decompresses body, reading the gzip header only once the body is read
+type gzipReadCloser struct {
+	body   io.ReadCloser
+	reader *gzip.Reader
+	err    error
+}
+
+func (r *gzipReadCloser) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	if r.reader == nil {
+		if r.reader, r.err = gzip.NewReader(r.body); r.err != nil {
+			// an empty body is simply empty
+			if r.err != io.EOF {
+				r.err = errors.WithMessage(r.err, "unable to decompress response body")
+			}
+			return 0, r.err
+		}
+	}
+
+	return r.reader.Read(p)
+}
+
+func (r *gzipReadCloser) Close() error {
+	return r.body.Close()
+}
+
+// decompressBody returns the decompressed body if header says it is gzip encoded. Otherwise, or if it cannot
+// be decompressed, it returns body as it is and false.
+func decompressBody(header http.Header, body []byte) ([]byte, bool) {
+	if !strings.EqualFold(header.Get(HeaderNameContentEncoding), encodingGzip) {
+		return body, false
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return body, false
+	}
+
+	decompressed, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return body, false
+	}
+
+	return decompressed, true
+}
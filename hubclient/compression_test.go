@@ -0,0 +1,252 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gzipBytes(t *testing.T, data string) []byte {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+// gzipServer answers with a gzip encoded project when the client accepts it
+func gzipServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := []byte(`{"name": "compressed"}`)
+		if r.Header.Get(HeaderNameAcceptEncoding) == "gzip" {
+			w.Header().Set(HeaderNameContentEncoding, "gzip")
+			body = gzipBytes(t, `{"name": "compressed"}`)
+		}
+		w.Header().Set(HeaderNameContentType, "application/json")
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestResponseDecompression(t *testing.T) {
+	server := gzipServer(t)
+
+	var wireBytes int64
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := server.Client().Transport.RoundTrip(req)
+		if err == nil {
+			wireBytes = resp.ContentLength
+		}
+		return resp, err
+	})
+
+	tests := map[string]Option{
+		"http client":      WithHTTPClient(server.Client()),
+		"custom transport": WithTransport(transport),
+	}
+
+	for name, option := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, err := New(server.URL, option, WithMaxResponseSize(100))
+			require.NoError(t, err)
+
+			project, err := client.GetProject(hubapi.ResourceLink{Href: server.URL + "/api/projects/1"})
+			require.NoError(t, err)
+			assert.Equal(t, "compressed", project.Name)
+
+			var text string
+			err, _ = client.HttpGetString(server.URL+"/api/projects/1", &text, []int{http.StatusOK})
+			require.NoError(t, err)
+			assert.Equal(t, `{"name": "compressed"}`, text)
+		})
+	}
+
+	assert.Equal(t, int64(len(gzipBytes(t, `{"name": "compressed"}`))), wireBytes)
+}
+
+func TestResponseDecompressionErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderNameContentEncoding, "gzip")
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"name": "not compressed"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+
+	_, err = client.GetProject(hubapi.ResourceLink{Href: server.URL + "/api/projects/1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to decompress response body")
+
+	// an empty body is fine despite the encoding
+	require.NoError(t, client.DeleteProject(server.URL+"/api/projects/1"))
+}
+
+// uploadServer records the bodies of the requests it receives, decompressed, and their encodings
+type uploadServer struct {
+	lock      sync.Mutex
+	bodies    []string
+	encodings []string
+	failures  int
+}
+
+func (s *uploadServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	body, _ := ioutil.ReadAll(r.Body)
+	if r.Header.Get(HeaderNameContentEncoding) == "gzip" {
+		reader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ = ioutil.ReadAll(reader)
+	}
+
+	s.bodies = append(s.bodies, string(body))
+	s.encodings = append(s.encodings, r.Header.Get(HeaderNameContentEncoding))
+
+	if s.failures > 0 {
+		s.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Location", r.URL.String())
+	if r.Method == http.MethodPut {
+		w.WriteHeader(http.StatusAccepted)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func TestRequestCompression(t *testing.T) {
+	uploads := &uploadServer{failures: 1}
+	server := httptest.NewServer(uploads)
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	client, err := New(server.URL,
+		WithHTTPClient(server.Client()),
+		WithRetryPolicy(policy),
+		WithRequestCompression(RequestCompression{Method: http.MethodPut, PathPrefix: hubapi.DeveloperScansApi, MinSize: 20}))
+	require.NoError(t, err)
+
+	large := `{"document": "` + strings.Repeat("x", 100) + `"}`
+	require.NoError(t, client.UploadBdioFiles(server.URL+"/api/developer-scans/1", []string{large, `{"small": 1}`}))
+
+	_, err = client.HttpPostString(server.URL+"/api/developer-scans", large, hubapi.ContentTypeRapidScanRequest, http.StatusCreated)
+	require.NoError(t, err)
+
+	// the failed upload of the first document is retried compressed as well
+	assert.Equal(t, []string{large, large, `{"small": 1}`, "", large}, uploads.bodies)
+	assert.Equal(t, []string{"gzip", "gzip", "", "", ""}, uploads.encodings)
+}
+
+func TestRequestCompressionMatchesWholePathSegments(t *testing.T) {
+	rule := RequestCompression{Method: http.MethodPut, PathPrefix: hubapi.DeveloperScansApi}
+	for path, matches := range map[string]bool{
+		hubapi.DeveloperScansApi:          true,
+		hubapi.DeveloperScansApi + "/1":   true,
+		hubapi.DeveloperScansApi + "-old": false,
+	} {
+		req, err := http.NewRequest(http.MethodPut, "https://blackduck"+path, nil)
+		require.NoError(t, err)
+		assert.Equal(t, matches, rule.matches(req), path)
+	}
+}
+
+func TestRequestCompressionOfFiles(t *testing.T) {
+	uploads := &uploadServer{}
+	server := httptest.NewServer(uploads)
+	defer server.Close()
+
+	client, err := New(server.URL,
+		WithHTTPClient(server.Client()),
+		WithRequestCompression(RequestCompression{PathPrefix: "/api/scan/data", MinSize: 1000}))
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	small := filepath.Join(dir, "small.jsonld")
+	large := filepath.Join(dir, "large.jsonld")
+	require.NoError(t, os.WriteFile(small, []byte(`{"small": 1}`), 0644))
+	require.NoError(t, os.WriteFile(large, []byte(strings.Repeat(`{"large": 1}`, 100)), 0644))
+
+	for _, file := range []string{small, large} {
+		_, status, err := client.HttpPostFile(server.URL+"/api/scan/data", file, "application/ld+json")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, status)
+	}
+
+	assert.Equal(t, []string{`{"small": 1}`, strings.Repeat(`{"large": 1}`, 100)}, uploads.bodies)
+	assert.Equal(t, []string{"", "gzip"}, uploads.encodings)
+}
+
+func TestCassetteRecordsDecompressedBodies(t *testing.T) {
+	server := gzipServer(t)
+	dir := t.TempDir()
+
+	cassette, err := NewCassette(dir, CassetteRecord)
+	require.NoError(t, err)
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithCassette(cassette))
+	require.NoError(t, err)
+
+	project, err := client.GetProject(hubapi.ResourceLink{Href: server.URL + "/api/projects/1"})
+	require.NoError(t, err)
+	assert.Equal(t, "compressed", project.Name)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "0001-GET-api-projects-id.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"body": "{\"name\": \"compressed\"}"`)
+	assert.NotContains(t, string(data), HeaderNameContentEncoding)
+
+	cassette, err = NewCassette(dir, CassetteReplay)
+	require.NoError(t, err)
+	client, err = New(server.URL, WithCassette(cassette))
+	require.NoError(t, err)
+
+	project, err = client.GetProject(hubapi.ResourceLink{Href: server.URL + "/api/projects/1"})
+	require.NoError(t, err)
+	assert.Equal(t, "compressed", project.Name)
+}
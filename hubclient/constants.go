@@ -15,9 +15,18 @@
 package hubclient
 
 const (
-	HeaderNameContentType   = "Content-Type"
-	HeaderNameAccept        = "Accept"
-	HeaderNameAuthorization = "Authorization"
-	HeaderNameCsrfToken     = "X-Csrf-Token"
-	HeaderNameUserAgent     = "User-Agent"
+	HeaderNameContentType       = "Content-Type"
+	HeaderNameAccept            = "Accept"
+	HeaderNameAuthorization     = "Authorization"
+	HeaderNameCsrfToken         = "X-Csrf-Token"
+	HeaderNameUserAgent         = "User-Agent"
+	HeaderNameRetryAfter        = "Retry-After"
+	HeaderNameETag              = "ETag"
+	HeaderNameLastModified      = "Last-Modified"
+	HeaderNameIfNoneMatch       = "If-None-Match"
+	HeaderNameIfModifiedSince   = "If-Modified-Since"
+	HeaderNameIfUnmodifiedSince = "If-Unmodified-Since"
+	HeaderNameCacheControl      = "Cache-Control"
+	HeaderNameContentEncoding   = "Content-Encoding"
+	HeaderNameAcceptEncoding    = "Accept-Encoding"
 )
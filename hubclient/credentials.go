@@ -0,0 +1,102 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"context"
+	"os"
+)
+
+// Environment variables read by EnvironmentCredentials
+const (
+	EnvApiToken    = "BLACKDUCK_API_TOKEN"
+	EnvBearerToken = "BLACKDUCK_BEARER_TOKEN"
+	EnvUsername    = "BLACKDUCK_USERNAME"
+	EnvPassword    = "BLACKDUCK_PASSWORD"
+)
+
+// CredentialProvider sets up the authentication of a Client when it is created by New
+type CredentialProvider interface {
+	// Authenticate configures the credentials of the client, logging in to the server where necessary
+	Authenticate(ctx context.Context, c *Client) error
+}
+
+// BearerTokenCredentials authenticates every request with a fixed bearer token
+type BearerTokenCredentials struct {
+	Token string
+}
+
+func (p BearerTokenCredentials) Authenticate(ctx context.Context, c *Client) error {
+	c.SetBearerToken(p.Token)
+	return nil
+}
+
+// ApiTokenCredentials exchanges an API token for a bearer token, which is refreshed before it expires
+type ApiTokenCredentials struct {
+	Token string
+}
+
+func (p ApiTokenCredentials) Authenticate(ctx context.Context, c *Client) error {
+	c.authLock.Lock()
+	c.apiToken = p.Token
+	c.authLock.Unlock()
+
+	if err := c.authenticateWithApiToken(ctx); err != nil {
+		return err
+	}
+
+	c.log().Debug("Logged in with auth token successfully")
+	return nil
+}
+
+// UsernamePasswordCredentials logs in with the form login and authenticates requests with the session cookie
+type UsernamePasswordCredentials struct {
+	Username string
+	Password string
+}
+
+func (p UsernamePasswordCredentials) Authenticate(ctx context.Context, c *Client) error {
+	return c.LoginContext(ctx, p.Username, p.Password)
+}
+
+// EnvironmentCredentials takes the credentials from the environment, using the first one available of
+// an API token (BLACKDUCK_API_TOKEN), a username and password (BLACKDUCK_USERNAME and BLACKDUCK_PASSWORD)
+// and a bearer token (BLACKDUCK_BEARER_TOKEN)
+type EnvironmentCredentials struct{}
+
+func (p EnvironmentCredentials) Authenticate(ctx context.Context, c *Client) error {
+	provider, err := p.provider()
+	if err != nil {
+		return err
+	}
+
+	return provider.Authenticate(ctx, c)
+}
+
+func (EnvironmentCredentials) provider() (CredentialProvider, error) {
+	if token := os.Getenv(EnvApiToken); token != "" {
+		return ApiTokenCredentials{Token: token}, nil
+	}
+
+	if username := os.Getenv(EnvUsername); username != "" {
+		return UsernamePasswordCredentials{Username: username, Password: os.Getenv(EnvPassword)}, nil
+	}
+
+	if token := os.Getenv(EnvBearerToken); token != "" {
+		return BearerTokenCredentials{Token: token}, nil
+	}
+
+	return nil, HubClientErrorf("no credentials found in the environment, set %s, %s and %s, or %s", EnvApiToken, EnvUsername, EnvPassword, EnvBearerToken)
+}
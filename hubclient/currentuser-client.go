@@ -15,11 +15,15 @@
 package hubclient
 
 import (
+	"context"
 	"github.com/blackducksoftware/hub-client-go/hubapi"
-	log "github.com/sirupsen/logrus"
 )
 
 func (c *Client) CreateApiToken(name, description string, readOnly bool) (location string, token string, err error) {
+	return c.CreateApiTokenContext(context.Background(), name, description, readOnly)
+}
+
+func (c *Client) CreateApiTokenContext(ctx context.Context, name, description string, readOnly bool) (location string, token string, err error) {
 	tokensUrl := hubapi.BuildUrl(c.baseURL, hubapi.CurrentUserTokensApi)
 
 	tokenRequest := &hubapi.ApiToken{
@@ -33,29 +37,37 @@ func (c *Client) CreateApiToken(name, description string, readOnly bool) (locati
 	}
 
 	var tokenResponse hubapi.CreateApiTokenResponse
-	location, err = c.HttpPostJSONExpectResult(tokensUrl, tokenRequest, &tokenResponse, "application/json", 201)
+	location, err = c.HttpPostJSONExpectResultContext(ctx, tokensUrl, tokenRequest, &tokenResponse, "application/json", 201)
 
 	if err != nil {
 		return location, "", TraceHubClientError(err)
 	}
 
 	if location == "" {
-		log.Warn("Did not get a location header back from token creation")
+		c.log().Warn("Did not get a location header back from token creation")
 	}
 
 	return location, tokenResponse.Token, err
 }
 
 func (c *Client) DeleteApiToken(tokenUrl string) error {
-	return c.HttpDelete(tokenUrl, "application/json", 204)
+	return c.DeleteApiTokenContext(context.Background(), tokenUrl)
+}
+
+func (c *Client) DeleteApiTokenContext(ctx context.Context, tokenUrl string) error {
+	return c.HttpDeleteContext(ctx, tokenUrl, "application/json", 204)
 }
 
 func (c *Client) ListApiTokens(options *hubapi.GetListOptions) (*hubapi.ApiTokenList, error) {
+	return c.ListApiTokensContext(context.Background(), options)
+}
+
+func (c *Client) ListApiTokensContext(ctx context.Context, options *hubapi.GetListOptions) (*hubapi.ApiTokenList, error) {
 	tokensUrl := hubapi.BuildUrl(c.baseURL, hubapi.CurrentUserTokensApi)
 
 	var apiTokenList hubapi.ApiTokenList
 
-	err := c.GetPage(tokensUrl, options, &apiTokenList)
+	err := c.GetPageContext(ctx, tokensUrl, options, &apiTokenList)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve api tokens list")
@@ -65,11 +77,15 @@ func (c *Client) ListApiTokens(options *hubapi.GetListOptions) (*hubapi.ApiToken
 }
 
 func (c *Client) GetCurrentUser() (response *hubapi.CurrentUserResponse, err error) {
+	return c.GetCurrentUserContext(context.Background())
+}
+
+func (c *Client) GetCurrentUserContext(ctx context.Context) (response *hubapi.CurrentUserResponse, err error) {
 	currentUserUrl := hubapi.BuildUrl(c.baseURL, hubapi.CurrentUserApi)
 
 	response = &hubapi.CurrentUserResponse{}
 
-	err = c.HttpGetJSON(currentUserUrl, response, 200)
+	err = c.HttpGetJSONContext(ctx, currentUserUrl, response, 200)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to get current user")
@@ -15,14 +15,19 @@
 package hubclient
 
 import (
+	"context"
 	"github.com/blackducksoftware/hub-client-go/hubapi"
 )
 
 func (c *Client) CurrentVersion() (*hubapi.CurrentVersion, error) {
+	return c.CurrentVersionContext(context.Background())
+}
+
+func (c *Client) CurrentVersionContext(ctx context.Context) (*hubapi.CurrentVersion, error) {
 	currentVersionURL := hubapi.BuildUrl(c.baseURL, hubapi.CurrentVersionApi)
 
 	var currentVersion hubapi.CurrentVersion
-	err := c.HttpGetJSON(currentVersionURL, &currentVersion, 200)
+	err := c.HttpGetJSONContext(ctx, currentVersionURL, &currentVersion, 200)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to get current version")
@@ -1,7 +1,8 @@
 package hubclient
 
 import (
-	log "github.com/sirupsen/logrus"
+	"net/http"
+	"time"
 )
 
 type HubClientDebug uint16
@@ -11,10 +12,24 @@ const (
 	HubClientDebugContent
 )
 
-func debugReportBytes(bodyBytes []byte, debugFlags HubClientDebug) {
-	if debugFlags&HubClientDebugContent != 0 {
-		log.Debugf("START DEBUG: --------------------------------------------------------------------------- \n\n")
-		log.Debugf("TEXT OF RESPONSE: \n %s", string(bodyBytes[:]))
-		log.Debugf("END DEBUG: --------------------------------------------------------------------------- \n\n\n\n")
+func (c *Client) debugReportBytes(bodyBytes []byte) {
+	if c.debugFlags&HubClientDebugContent != 0 {
+		c.log().Debug("Text of response", Field{"body", string(bodyBytes)})
 	}
 }
+
+func (c *Client) debugRequestStart(method string, url string) {
+	if c.debugFlags&HubClientDebugTimings != 0 {
+		c.log().Debug("Starting HTTP request", methodField(method), urlField(url))
+	}
+}
+
+func (c *Client) debugRequestElapsed(method string, url string, start time.Time) {
+	if c.debugFlags&HubClientDebugTimings != 0 {
+		c.log().Debug("HTTP request finished", methodField(method), urlField(url), durationField(time.Since(start)))
+	}
+}
+
+func (c *Client) debugRequest(req *http.Request) {
+	c.log().Debug("Sending HTTP request", methodField(req.Method), urlField(req.URL.String()), Field{"header", maskAuthHeader(req).Header})
+}
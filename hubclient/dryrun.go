@@ -0,0 +1,187 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/pkg/errors"
+)
+
+// DryRun records the mutating requests of a Client instead of sending them, see SetDryRun
+type DryRun struct {
+	lock      sync.Mutex
+	mutations []Mutation
+}
+
+// Mutation is a request recorded by a DryRun
+type Mutation struct {
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	ContentType string `json:"contentType,omitempty"`
+	// Body is the request body if it is text, BodyBase64 the base64 encoded body otherwise
+	Body       string `json:"body,omitempty"`
+	BodyBase64 string `json:"bodyBase64,omitempty"`
+	// ExpectedStatus are the status codes the caller accepts, the first of which is answered
+	ExpectedStatus []int `json:"expectedStatus"`
+	// Location is the synthetic Location header answered to a POST
+	Location string `json:"location,omitempty"`
+}
+
+// NewDryRun creates an empty DryRun
+func NewDryRun() *DryRun {
+	return &DryRun{}
+}
+
+// Mutations returns the requests recorded so far, in the order they were made
+func (d *DryRun) Mutations() []Mutation {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return append([]Mutation(nil), d.mutations...)
+}
+
+// WriteJSON writes the recorded requests to w as a JSON array, the plan of what the Client would have done
+func (d *DryRun) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	mutations := d.Mutations()
+	if mutations == nil {
+		mutations = []Mutation{}
+	}
+
+	return errors.WithMessage(enc.Encode(mutations), "unable to write dry run")
+}
+
+// SetDryRun makes the Client record every request other than GET, HEAD and OPTIONS into d instead of sending
+// it, such as those of CreateProject, DeleteProjectVersion or AssignUserToProject. The authentication requests
+// are still sent, so that the reads in between work. Each recorded request is answered with its first
+// expected status code and an empty JSON object, and a POST additionally with a Location made of its URL
+// and a made up id, so that scripts creating resources carry on. Reading such a Location from the server
+// fails of course. A nil d turns the dry run off.
+func (c *Client) SetDryRun(d *DryRun) {
+	c.dryRun = d
+}
+
+// WithDryRun makes the Client record its mutating requests into d, see SetDryRun
+func WithDryRun(d *DryRun) Option {
+	return func(o *clientOptions) error {
+		o.dryRun = d
+		return nil
+	}
+}
+
+// record answers req in place of the server. It reports false if req is not to be recorded.
+func (d *DryRun) record(req *http.Request) (*http.Response, bool, error) {
+	if !isMutation(req) {
+		return nil, false, nil
+	}
+
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, true, err
+	}
+
+	// the plan is meant to be shared, so passwords are left out like in a Cassette
+	recorded := newCassetteBody(redactBody(body))
+	mutation := Mutation{
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		ContentType:    req.Header.Get(HeaderNameContentType),
+		Body:           recorded.Body,
+		BodyBase64:     recorded.BodyBase64,
+		ExpectedStatus: expectedStatusOf(req),
+	}
+
+	d.lock.Lock()
+	if req.Method == http.MethodPost {
+		mutation.Location = fmt.Sprintf("%s/00000000-0000-0000-0000-%012d", strings.TrimSuffix(req.URL.String(), "/"), len(d.mutations)+1)
+	}
+	d.mutations = append(d.mutations, mutation)
+	d.lock.Unlock()
+
+	resp := &http.Response{
+		Status:        fmt.Sprintf("%d %s", mutation.ExpectedStatus[0], http.StatusText(mutation.ExpectedStatus[0])),
+		StatusCode:    mutation.ExpectedStatus[0],
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{HeaderNameContentType: {"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte("{}"))),
+		ContentLength: 2,
+		Request:       req,
+	}
+	if mutation.Location != "" {
+		resp.Header.Set("Location", mutation.Location)
+	}
+
+	return resp, true, nil
+}
+
+// isMutation reports whether req may change something on the server, leaving out logging in
+func isMutation(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+
+	return !strings.HasSuffix(req.URL.Path, hubapi.SecurityApi) && !strings.HasSuffix(req.URL.Path, hubapi.AuthenticateApi)
+}
+
+type expectedStatusKey struct{}
+
+// withExpectedStatus returns a copy of req which remembers the status codes its caller accepts
+func withExpectedStatus(req *http.Request, codes ...int) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), expectedStatusKey{}, codes))
+}
+
+// expectedStatusOf returns the status codes accepted for req, those usual for its method if not known
+func expectedStatusOf(req *http.Request) []int {
+	if codes, _ := req.Context().Value(expectedStatusKey{}).([]int); len(codes) > 0 {
+		return codes
+	}
+
+	if req.Method == http.MethodPost {
+		return []int{http.StatusCreated}
+	}
+	return []int{http.StatusNoContent}
+}
+
+// madeUp reports whether href is, or is below, a Location made up by the dry run
+func (d *DryRun) madeUp(href string) bool {
+	if d == nil || href == "" {
+		return false
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	for _, m := range d.mutations {
+		if m.Location != "" && (href == m.Location || strings.HasPrefix(href, m.Location+"/")) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunRecordsMutations(t *testing.T) {
+	ts := &tokenServer{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.URL.Path != hubapi.AuthenticateApi {
+			t.Errorf("unexpected %s to %s", r.Method, r.URL.Path)
+		}
+		ts.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	dryRun := NewDryRun()
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithCredentials(ApiTokenCredentials{Token: "api-token"}), WithDryRun(dryRun))
+	require.NoError(t, err)
+
+	location, err := client.CreateProject(&hubapi.ProjectRequest{Name: "project"})
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+hubapi.ProjectsApi+"/00000000-0000-0000-0000-000000000001", location)
+
+	_, err = client.AssignUserToProject(hubapi.ResourceLink{Href: location + "/users"}, &hubapi.UserAssignmentRequest{User: server.URL + "/api/users/1"})
+	require.NoError(t, err)
+	_, err = client.CreateUser(&hubapi.UserRequest{UserName: "user", Password: "secret"})
+	require.NoError(t, err)
+	_, err = client.CreatePolicyRule(&hubapi.PolicyRuleRequest{Name: "rule"})
+	require.NoError(t, err)
+	require.NoError(t, client.UpdateExternalExtension(&hubapi.ExternalExtension{Name: "extension", Meta: hubapi.Meta{Href: server.URL + "/api/external-extensions/1"}}))
+	require.NoError(t, client.DeleteProjectVersion(location+"/versions/1"))
+	require.NoError(t, client.DeleteCodeLocation(server.URL+"/api/codelocations/1"))
+
+	// reads still reach the server
+	_, err = client.CurrentVersion()
+	require.NoError(t, err)
+
+	mutations := dryRun.Mutations()
+	require.Len(t, mutations, 7)
+
+	var methods []string
+	for _, m := range mutations {
+		methods = append(methods, m.Method+" "+m.URL[len(server.URL):])
+	}
+	assert.Equal(t, []string{
+		"POST " + hubapi.ProjectsApi,
+		"POST " + hubapi.ProjectsApi + "/00000000-0000-0000-0000-000000000001/users",
+		"POST " + hubapi.UsersApi,
+		"POST " + hubapi.PolicyRulesApi,
+		"PUT /api/external-extensions/1",
+		"DELETE " + hubapi.ProjectsApi + "/00000000-0000-0000-0000-000000000001/versions/1",
+		"DELETE /api/codelocations/1",
+	}, methods)
+
+	assert.JSONEq(t, `{"user": "`+server.URL+`/api/users/1"}`, mutations[1].Body)
+	assert.Equal(t, location+"/users/00000000-0000-0000-0000-000000000002", mutations[1].Location)
+	assert.Contains(t, mutations[2].Body, `"password":"REDACTED"`)
+	assert.NotContains(t, mutations[2].Body, "secret")
+	assert.Equal(t, hubapi.ContentTypeExtensionJSON, mutations[4].ContentType)
+	assert.Equal(t, []int{http.StatusOK}, mutations[4].ExpectedStatus)
+	assert.Equal(t, []int{http.StatusNoContent}, mutations[6].ExpectedStatus)
+	assert.Empty(t, mutations[6].Location)
+
+	var buf bytes.Buffer
+	require.NoError(t, dryRun.WriteJSON(&buf))
+	var exported []Mutation
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &exported))
+	assert.Equal(t, mutations, exported)
+}
+
+func TestDryRunCanBeTurnedOff(t *testing.T) {
+	client := statusServer(t, http.StatusNoContent, "")
+	dryRun := NewDryRun()
+
+	client.SetDryRun(dryRun)
+	require.NoError(t, client.HttpDelete(client.BaseURL()+"/api/projects/1", "application/json", http.StatusNoContent))
+	client.SetDryRun(nil)
+	require.NoError(t, client.HttpDelete(client.BaseURL()+"/api/projects/2", "application/json", http.StatusNoContent))
+
+	require.Len(t, dryRun.Mutations(), 1)
+	assert.Equal(t, client.BaseURL()+"/api/projects/1", dryRun.Mutations()[0].URL)
+
+	var buf bytes.Buffer
+	require.NoError(t, NewDryRun().WriteJSON(&buf))
+	assert.Equal(t, "[]\n", buf.String())
+}
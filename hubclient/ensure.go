@@ -0,0 +1,180 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/pkg/errors"
+)
+
+// EnsureProject returns the project named like projectRequest, creating it from projectRequest if there is
+// none. Names are matched exactly, not by prefix as the q parameter of the projects API does. The version
+// of the request, if any, gets the DEVELOPMENT phase and EXTERNAL distribution unless they are set. Should
+// another client create the project at the same time, the one it created is returned.
+func (c *Client) EnsureProject(projectRequest *hubapi.ProjectRequest) (*hubapi.Project, error) {
+	return c.EnsureProjectContext(context.Background(), projectRequest)
+}
+
+func (c *Client) EnsureProjectContext(ctx context.Context, projectRequest *hubapi.ProjectRequest) (*hubapi.Project, error) {
+	name := projectRequest.Name
+
+	project, err := c.findProject(ctx, name)
+	if err != nil || project != nil {
+		return project, err
+	}
+
+	request := *projectRequest
+	if request.VersionRequest != nil {
+		request.VersionRequest = withVersionDefaults(request.VersionRequest)
+	}
+
+	location, err := c.CreateProjectContext(ctx, &request)
+	if err != nil || location == "" {
+		if err != nil && !isCreationConflict(err) {
+			return nil, AnnotateHubClientErrorf(err, "unable to create project %s", name)
+		}
+
+		// someone else created it in the meantime, or the server did not tell where it is
+		if project, findErr := c.findProject(ctx, name); findErr != nil || project != nil {
+			return project, findErr
+		}
+		if err == nil {
+			err = HubClientErrorf("project %s was created but cannot be found", name)
+		}
+		return nil, AnnotateHubClientErrorf(err, "unable to create project %s", name)
+	}
+
+	if c.dryRun.madeUp(location) {
+		return &hubapi.Project{
+			Name:        request.Name,
+			Description: request.Description,
+			Meta: hubapi.Meta{
+				Href:  location,
+				Links: []hubapi.ResourceLink{{Rel: hubapi.ProjectRelVersions, Href: location + "/versions"}},
+			},
+		}, nil
+	}
+
+	return c.GetProjectContext(ctx, hubapi.ResourceLink{Href: location})
+}
+
+// EnsureProjectVersion returns the version of project named like versionRequest, creating it from
+// versionRequest if there is none, see EnsureProject
+func (c *Client) EnsureProjectVersion(project *hubapi.Project, versionRequest *hubapi.ProjectVersionRequest) (*hubapi.ProjectVersion, error) {
+	return c.EnsureProjectVersionContext(context.Background(), project, versionRequest)
+}
+
+func (c *Client) EnsureProjectVersionContext(ctx context.Context, project *hubapi.Project, versionRequest *hubapi.ProjectVersionRequest) (*hubapi.ProjectVersion, error) {
+	link, err := findLink(&project.Meta, hubapi.ProjectRelVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	name := versionRequest.VersionName
+
+	version, err := c.findProjectVersion(ctx, link.Href, name)
+	if err != nil || version != nil {
+		return version, err
+	}
+
+	request := withVersionDefaults(versionRequest)
+
+	location, err := c.CreateProjectVersionContext(ctx, *link, request)
+	if err != nil || location == "" {
+		if err != nil && !isCreationConflict(err) {
+			return nil, AnnotateHubClientErrorf(err, "unable to create version %s of project %s", name, project.Name)
+		}
+
+		if version, findErr := c.findProjectVersion(ctx, link.Href, name); findErr != nil || version != nil {
+			return version, findErr
+		}
+		if err == nil {
+			err = HubClientErrorf("version %s of project %s was created but cannot be found", name, project.Name)
+		}
+		return nil, AnnotateHubClientErrorf(err, "unable to create version %s of project %s", name, project.Name)
+	}
+
+	if c.dryRun.madeUp(location) {
+		return &hubapi.ProjectVersion{
+			VersionName:  request.VersionName,
+			Nickname:     request.Nickname,
+			Phase:        request.Phase,
+			Distribution: request.Distribution,
+			Meta: hubapi.Meta{
+				Href:  location,
+				Links: []hubapi.ResourceLink{{Rel: hubapi.ProjectVersionRelProject, Href: project.Meta.Href}},
+			},
+		}, nil
+	}
+
+	return c.GetProjectVersionContext(ctx, hubapi.ResourceLink{Href: location})
+}
+
+// findProject returns the project with exactly the given name, nil if there is none
+func (c *Client) findProject(ctx context.Context, name string) (*hubapi.Project, error) {
+	q := "name:" + name
+	stream := NewItemStream[hubapi.Project](ctx, c, hubapi.BuildUrl(c.baseURL, hubapi.ProjectsApi), &hubapi.GetListOptions{Q: &q})
+	defer stream.Close()
+
+	for stream.Next() {
+		if project := stream.Item(); project.Name == name {
+			return &project, nil
+		}
+	}
+
+	return nil, AnnotateHubClientErrorf(stream.Err(), "unable to look up project %s", name)
+}
+
+// findProjectVersion returns the version with exactly the given name from the list at versionsURL, nil if
+// there is none
+func (c *Client) findProjectVersion(ctx context.Context, versionsURL string, name string) (*hubapi.ProjectVersion, error) {
+	if c.dryRun.madeUp(versionsURL) {
+		// the project only exists in the dry run
+		return nil, nil
+	}
+
+	q := "versionName:" + name
+	stream := NewItemStream[hubapi.ProjectVersion](ctx, c, versionsURL, &hubapi.GetListOptions{Q: &q})
+	defer stream.Close()
+
+	for stream.Next() {
+		if version := stream.Item(); version.VersionName == name {
+			return &version, nil
+		}
+	}
+
+	return nil, AnnotateHubClientErrorf(stream.Err(), "unable to look up project version %s", name)
+}
+
+func withVersionDefaults(versionRequest *hubapi.ProjectVersionRequest) *hubapi.ProjectVersionRequest {
+	request := *versionRequest
+	if request.Phase == "" {
+		request.Phase = hubapi.ProjectVersionPhaseDevelopment
+	}
+	if request.Distribution == "" {
+		request.Distribution = hubapi.ProjectVersionDistributionExternal
+	}
+	return &request
+}
+
+// isCreationConflict reports whether creating a resource failed because its name is taken, which Black Duck
+// answers with 412 Precondition Failed or 409 Conflict
+func isCreationConflict(err error) bool {
+	var hce *HubClientError
+	return errors.Is(err, ErrConflict) || errors.As(err, &hce) && hce.StatusCode == http.StatusPreconditionFailed
+}
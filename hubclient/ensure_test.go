@@ -0,0 +1,164 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/blackducksoftware/hub-client-go/hubclient"
+	"github.com/blackducksoftware/hub-client-go/hubtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) (*hubtest.Server, *hubclient.Client) {
+	server := hubtest.NewServer()
+	t.Cleanup(server.Close)
+
+	client, err := server.NewClient()
+	require.NoError(t, err)
+
+	return server, client
+}
+
+// created returns the paths of the projects and versions the server was asked to create
+func created(server *hubtest.Server) []string {
+	var paths []string
+	for _, r := range server.Requests() {
+		if r.Method == http.MethodPost && strings.HasPrefix(r.Path, hubapi.ProjectsApi) {
+			paths = append(paths, r.Path)
+		}
+	}
+	return paths
+}
+
+func TestEnsureProjectMatchesNamesExactly(t *testing.T) {
+	server, client := newTestServer(t)
+	_, err := server.AddProject(hubapi.ProjectRequest{Name: "demo-legacy"})
+	require.NoError(t, err)
+	existing, err := server.AddProject(hubapi.ProjectRequest{Name: "demo"})
+	require.NoError(t, err)
+
+	project, err := client.EnsureProject(&hubapi.ProjectRequest{Name: "demo"})
+	require.NoError(t, err)
+	assert.Equal(t, existing, project.Meta.Href)
+
+	project, err = client.EnsureProject(&hubapi.ProjectRequest{Name: "dem"})
+	require.NoError(t, err)
+	assert.Equal(t, "dem", project.Name)
+	assert.NotEqual(t, existing, project.Meta.Href)
+	assert.Equal(t, []string{hubapi.ProjectsApi}, created(server))
+}
+
+func TestEnsureProjectVersionAppliesDefaults(t *testing.T) {
+	server, client := newTestServer(t)
+
+	project, err := client.EnsureProject(&hubapi.ProjectRequest{Name: "demo", VersionRequest: &hubapi.ProjectVersionRequest{VersionName: "1.0"}})
+	require.NoError(t, err)
+
+	version, err := client.EnsureProjectVersion(project, &hubapi.ProjectVersionRequest{VersionName: "1.0"})
+	require.NoError(t, err)
+	assert.Equal(t, hubapi.ProjectVersionPhaseDevelopment, version.Phase)
+	assert.Equal(t, hubapi.ProjectVersionDistributionExternal, version.Distribution)
+
+	request := &hubapi.ProjectVersionRequest{VersionName: "1", Phase: hubapi.ProjectVersionPhaseReleased}
+	version, err = client.EnsureProjectVersion(project, request)
+	require.NoError(t, err)
+	assert.Equal(t, "1", version.VersionName)
+	assert.Equal(t, hubapi.ProjectVersionPhaseReleased, version.Phase)
+	assert.Equal(t, hubapi.ProjectVersionDistributionExternal, version.Distribution)
+	assert.Empty(t, request.Distribution, "the request of the caller is left alone")
+
+	projectPath := strings.TrimPrefix(project.Meta.Href, server.URL)
+	assert.Equal(t, []string{hubapi.ProjectsApi, projectPath + "/versions"}, created(server))
+}
+
+func TestEnsureProjectRecoversFromConcurrentCreation(t *testing.T) {
+	server, client := newTestServer(t)
+
+	var raced string
+	server.InjectFault(hubtest.Fault{
+		Method:     http.MethodPost,
+		PathPrefix: hubapi.ProjectsApi,
+		Times:      1,
+		Before: func() {
+			var err error
+			raced, err = server.AddProject(hubapi.ProjectRequest{Name: "demo"})
+			assert.NoError(t, err)
+		},
+	})
+	project, err := client.EnsureProject(&hubapi.ProjectRequest{Name: "demo"})
+	require.NoError(t, err)
+	assert.Equal(t, raced, project.Meta.Href)
+
+	projectPath := strings.TrimPrefix(project.Meta.Href, server.URL)
+	server.InjectFault(hubtest.Fault{
+		Method:     http.MethodPost,
+		PathPrefix: projectPath + "/versions",
+		Times:      1,
+		Before: func() {
+			var err error
+			raced, err = server.AddProjectVersion(project.Meta.Href, hubapi.ProjectVersionRequest{
+				VersionName:  "1.0",
+				Phase:        hubapi.ProjectVersionPhasePlanning,
+				Distribution: hubapi.ProjectVersionDistributionInternal,
+			})
+			assert.NoError(t, err)
+		},
+	})
+	version, err := client.EnsureProjectVersion(project, &hubapi.ProjectVersionRequest{VersionName: "1.0"})
+	require.NoError(t, err)
+	assert.Equal(t, raced, version.Meta.Href)
+	assert.Equal(t, hubapi.ProjectVersionPhasePlanning, version.Phase, "the version of the other client is returned")
+
+	assert.Equal(t, []string{hubapi.ProjectsApi, projectPath + "/versions"}, created(server))
+}
+
+func TestEnsureProjectFailsOnOtherErrors(t *testing.T) {
+	server, client := newTestServer(t)
+	server.InjectFault(hubtest.Fault{
+		Method:     http.MethodPost,
+		PathPrefix: hubapi.ProjectsApi,
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  "{central.constraint_violation.name_required}",
+	})
+
+	_, err := client.EnsureProject(&hubapi.ProjectRequest{Name: "demo"})
+	require.Error(t, err)
+	var hce *hubclient.HubClientError
+	require.True(t, errors.As(err, &hce))
+	assert.Equal(t, http.StatusBadRequest, hce.StatusCode)
+	assert.False(t, errors.Is(err, hubclient.ErrConflict))
+	assert.Len(t, created(server), 1)
+}
+
+func TestEnsureProjectInDryRun(t *testing.T) {
+	server, client := newTestServer(t)
+	dryRun := hubclient.NewDryRun()
+	client.SetDryRun(dryRun)
+
+	project, err := client.EnsureProject(&hubapi.ProjectRequest{Name: "demo"})
+	require.NoError(t, err)
+	version, err := client.EnsureProjectVersion(project, &hubapi.ProjectVersionRequest{VersionName: "1.0"})
+	require.NoError(t, err)
+
+	assert.Equal(t, project.Meta.Href+"/versions/00000000-0000-0000-0000-000000000002", version.Meta.Href)
+	assert.Len(t, dryRun.Mutations(), 2)
+	assert.Empty(t, created(server))
+}
@@ -15,13 +15,48 @@
 package hubclient
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
 	"github.com/pkg/errors"
 )
 
+// sentinelError is the type of the errors below, which errors returned by the Client can be matched with errors.Is
+type sentinelError string
+
+func (e sentinelError) Error() string { return string(e) }
+
+// Errors matched by errors.Is for the errors returned by the Client, according to the response status or
+// the cause of the error
+var (
+	// ErrNotFound matches 404 responses
+	ErrNotFound error = sentinelError("not found")
+	// ErrUnauthorized matches 401 responses
+	ErrUnauthorized error = sentinelError("unauthorized")
+	// ErrForbidden matches 403 responses
+	ErrForbidden error = sentinelError("forbidden")
+	// ErrConflict matches 409 responses
+	ErrConflict error = sentinelError("conflict")
+	// ErrRateLimited matches 429 responses
+	ErrRateLimited error = sentinelError("rate limited")
+	// ErrServerUnavailable matches 502, 503 and 504 responses
+	ErrServerUnavailable error = sentinelError("server unavailable")
+	// ErrTimeout matches 408 responses and requests which timed out or whose context deadline passed
+	ErrTimeout error = sentinelError("timeout")
+	// ErrResponseTooLarge matches responses whose body exceeds the maximum size, see SetMaxResponseSize
+	ErrResponseTooLarge error = sentinelError("response too large")
+	// ErrLinkNotFound matches following a link which the resource does not have, see Client.Follow
+	ErrLinkNotFound error = sentinelError("link not found")
+)
+
 type HubClientError struct {
 	Err        error
 	StatusCode int
 	HubError   HubResponseError
+	// Attempts is the number of times the request was sent, or 0 if that is not known
+	Attempts int
 }
 
 func (e HubClientError) Error() string {
@@ -32,11 +67,109 @@ func (e HubClientError) Cause() error {
 	return errors.Cause(e.Err)
 }
 
+func (e HubClientError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error matches one of the sentinel errors such as ErrNotFound
+func (e HubClientError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServerUnavailable:
+		switch e.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	case ErrTimeout:
+		return e.StatusCode == http.StatusRequestTimeout || isTimeout(e.Err)
+	}
+
+	return false
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// FieldViolation is a problem with a single field of a request reported by Black Duck
+type FieldViolation struct {
+	Field        string
+	Type         string
+	Message      string
+	InvalidValue string
+	ErrorCode    string
+}
+
+func (e FieldViolation) Error() string {
+	if e.InvalidValue != "" {
+		return fmt.Sprintf("%s: %s (invalid value %q)", e.Field, e.Message, e.InvalidValue)
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// FieldViolations is the list of problems with single fields of a request
+type FieldViolations []FieldViolation
+
+// Field returns the problems with the named field
+func (l FieldViolations) Field(name string) FieldViolations {
+	var found FieldViolations
+	for _, e := range l {
+		if e.Field == name {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// FieldViolations returns the problems with single fields of the request reported in the response, if any
+func (e HubClientError) FieldViolations() FieldViolations {
+	return e.HubError.fieldViolations(nil)
+}
+
+func (e HubResponseError) fieldViolations(list FieldViolations) FieldViolations {
+	if e.Arguments.FieldName != "" {
+		message := e.Arguments.Message
+		if message == "" {
+			message = e.ErrorMessage
+		}
+
+		list = append(list, FieldViolation{
+			Field:        e.Arguments.FieldName,
+			Type:         e.Arguments.Type,
+			Message:      message,
+			InvalidValue: e.Arguments.InvalidValue,
+			ErrorCode:    e.ErrorCode,
+		})
+	}
+
+	for _, nested := range e.Errors {
+		list = nested.fieldViolations(list)
+	}
+
+	return list
+}
+
 type HubResponseError struct {
 	ErrorMessage string                   `json:"errorMessage"`
 	Arguments    HubResponseErrorArgument `json:"arguments"`
 	Errors       []HubResponseError       `json:"errors"`
 	ErrorCode    string                   `json:"errorCode"`
+	// LogRef identifies the error in the logs of the Black Duck server
+	LogRef string `json:"logRef,omitempty"`
 }
 
 type HubResponseErrorArgument struct {
@@ -59,7 +192,7 @@ func AnnotateHubClientError(old error, format string) error {
 	}
 
 	newErr := errors.WithMessage(old, format)
-	err := &HubClientError{newErr, hce.StatusCode, hce.HubError}
+	err := &HubClientError{newErr, hce.StatusCode, hce.HubError, hce.Attempts}
 	return err
 }
 
@@ -76,7 +209,7 @@ func AnnotateHubClientErrorf(old error, format string, args ...interface{}) erro
 	}
 
 	newErr := errors.WithMessagef(old, format, args...)
-	err := &HubClientError{newErr, hce.StatusCode, hce.HubError}
+	err := &HubClientError{newErr, hce.StatusCode, hce.HubError, hce.Attempts}
 	return err
 }
 
@@ -93,7 +226,7 @@ func TraceHubClientError(old error) error {
 	}
 
 	newErr := errors.WithStack(old)
-	err := &HubClientError{newErr, hce.StatusCode, hce.HubError}
+	err := &HubClientError{newErr, hce.StatusCode, hce.HubError, hce.Attempts}
 	return err
 }
 
@@ -103,7 +236,7 @@ func HubClientErrorf(format string, args ...interface{}) error {
 
 func HubClientStatusCodeErrorf(statusCode int, format string, args ...interface{}) error {
 	newErr := errors.Errorf(format, args...)
-	err := &HubClientError{newErr, statusCode, HubResponseError{}}
+	err := &HubClientError{newErr, statusCode, HubResponseError{}, 0}
 	return err
 }
 
@@ -114,3 +247,4 @@ type errorWithMessage struct {
 
 func (e *errorWithMessage) Error() string { return e.message }
 func (e *errorWithMessage) Cause() error  { return e.err }
+func (e *errorWithMessage) Unwrap() error { return e.err }
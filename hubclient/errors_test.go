@@ -0,0 +1,152 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statusServer(t *testing.T, status int, body string) *Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(queryDuration(r, "sleep"))
+		w.Header().Set(HeaderNameContentType, "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+	return client
+}
+
+func queryDuration(r *http.Request, name string) time.Duration {
+	d, _ := time.ParseDuration(r.URL.Query().Get(name))
+	return d
+}
+
+func TestSentinelErrors(t *testing.T) {
+	sentinels := []error{ErrNotFound, ErrUnauthorized, ErrForbidden, ErrConflict, ErrRateLimited, ErrServerUnavailable, ErrTimeout}
+
+	tests := []struct {
+		status   int
+		expected []error
+	}{
+		{http.StatusNotFound, []error{ErrNotFound}},
+		{http.StatusUnauthorized, []error{ErrUnauthorized}},
+		{http.StatusForbidden, []error{ErrForbidden}},
+		{http.StatusConflict, []error{ErrConflict}},
+		{http.StatusTooManyRequests, []error{ErrRateLimited}},
+		{http.StatusBadGateway, []error{ErrServerUnavailable}},
+		{http.StatusServiceUnavailable, []error{ErrServerUnavailable}},
+		{http.StatusGatewayTimeout, []error{ErrServerUnavailable}},
+		{http.StatusRequestTimeout, []error{ErrTimeout}},
+		{http.StatusInternalServerError, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(http.StatusText(test.status), func(t *testing.T) {
+			client := statusServer(t, test.status, `{"errorMessage": "failed"}`)
+
+			_, err := client.GetProject(hubapi.ResourceLink{Href: client.BaseURL() + "/api/projects/1"})
+			require.Error(t, err)
+
+			for _, sentinel := range sentinels {
+				expected := false
+				for _, e := range test.expected {
+					expected = expected || e == sentinel
+				}
+				assert.Equal(t, expected, errors.Is(err, sentinel), "errors.Is(%v)", sentinel)
+			}
+		})
+	}
+}
+
+func TestSentinelErrorsOfUnannotatedMethods(t *testing.T) {
+	client := statusServer(t, http.StatusNotFound, `{}`)
+	link := hubapi.ResourceLink{Href: client.BaseURL() + "/api/components/1"}
+
+	_, err := client.GetComponent(link)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Contains(t, err.Error(), "Error trying to retrieve a component")
+
+	_, err = client.GetVulnerability(link)
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	err, _ = statusServer(t, http.StatusNotFound, "no such page").CheckHubReadiness()
+	var hce *HubClientError
+	require.True(t, errors.As(err, &hce))
+	assert.Equal(t, http.StatusNotFound, hce.StatusCode)
+}
+
+func TestTimeoutErrors(t *testing.T) {
+	client := statusServer(t, http.StatusOK, `{}`)
+	client.httpClient.Timeout = 20 * time.Millisecond
+
+	_, err := client.GetProject(hubapi.ResourceLink{Href: client.BaseURL() + "/api/projects/1?sleep=200ms"})
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.False(t, errors.Is(err, ErrServerUnavailable))
+
+	client.httpClient.Timeout = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = client.GetProjectContext(ctx, hubapi.ResourceLink{Href: client.BaseURL() + "/api/projects/1?sleep=200ms"})
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	err, _ = client.PollRapidScanResults(client.BaseURL()+"/api/developer-scans/1", time.Hour, time.Millisecond, 10, RapidScanOpts{})
+	assert.True(t, errors.Is(err, ErrTimeout))
+}
+
+func TestFieldViolations(t *testing.T) {
+	client := statusServer(t, http.StatusBadRequest, `{
+		"errorMessage": "The request is invalid",
+		"errorCode": "{central.constraint_violation}",
+		"errors": [
+			{
+				"errorMessage": "The name is required",
+				"errorCode": "{central.constraint_violation.project_name_required}",
+				"arguments": {"fieldname": "name", "type": "string", "invalidValue": ""}
+			},
+			{
+				"errorCode": "{central.constraint_violation.project_tier_invalid}",
+				"arguments": {"fieldname": "projectTier", "message": "must be between 1 and 5", "invalidValue": "9"}
+			}
+		]
+	}`)
+
+	_, err := client.CreateProject(&hubapi.ProjectRequest{ProjectTier: new(int)})
+	var hce *HubClientError
+	require.True(t, errors.As(err, &hce))
+
+	violations := hce.FieldViolations()
+	assert.Equal(t, FieldViolations{
+		{Field: "name", Type: "string", Message: "The name is required", ErrorCode: "{central.constraint_violation.project_name_required}"},
+		{Field: "projectTier", Message: "must be between 1 and 5", InvalidValue: "9", ErrorCode: "{central.constraint_violation.project_tier_invalid}"},
+	}, violations)
+	assert.Len(t, violations.Field("projectTier"), 1)
+	assert.Empty(t, violations.Field("description"))
+	assert.Equal(t, `projectTier: must be between 1 and 5 (invalid value "9")`, violations[1].Error())
+}
@@ -14,13 +14,21 @@
 
 package hubclient
 
-import "github.com/blackducksoftware/hub-client-go/hubapi"
+import (
+	"context"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+)
 
 func (c *Client) DetectURI() (*hubapi.DetectURI, error) {
+	return c.DetectURIContext(context.Background())
+}
+
+func (c *Client) DetectURIContext(ctx context.Context) (*hubapi.DetectURI, error) {
 	detectURIURL := hubapi.BuildUrl(c.baseURL, hubapi.DetectUriApi)
 
 	var detectURI hubapi.DetectURI
-	err := c.HttpGetJSON(detectURIURL, &detectURI, 200)
+	err := c.HttpGetJSONContext(ctx, detectURIURL, &detectURI, 200)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to get Detect URI")
@@ -15,13 +15,18 @@
 package hubclient
 
 import (
+	"context"
 	"github.com/blackducksoftware/hub-client-go/hubapi"
 )
 
 func (c *Client) GetExternalExtension(link hubapi.ResourceLink) (*hubapi.ExternalExtension, error) {
+	return c.GetExternalExtensionContext(context.Background(), link)
+}
+
+func (c *Client) GetExternalExtensionContext(ctx context.Context, link hubapi.ResourceLink) (*hubapi.ExternalExtension, error) {
 
 	var extension hubapi.ExternalExtension
-	err := c.HttpGetJSON(link.Href, &extension, 200)
+	err := c.HttpGetJSONContext(ctx, link.Href, &extension, 200)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve an external extension")
@@ -31,8 +36,12 @@ func (c *Client) GetExternalExtension(link hubapi.ResourceLink) (*hubapi.Externa
 }
 
 func (c *Client) UpdateExternalExtension(extension *hubapi.ExternalExtension) error {
+	return c.UpdateExternalExtensionContext(context.Background(), extension)
+}
+
+func (c *Client) UpdateExternalExtensionContext(ctx context.Context, extension *hubapi.ExternalExtension) error {
 
-	err := c.HttpPutJSON(extension.Meta.Href, &extension, hubapi.ContentTypeExtensionJSON, 200)
+	err := c.HttpPutJSONContext(ctx, extension.Meta.Href, &extension, hubapi.ContentTypeExtensionJSON, 200)
 
 	if err != nil {
 		return AnnotateHubClientError(err, "Error trying to update an external extension")
@@ -0,0 +1,88 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+)
+
+// Follow gets the resource at the first link of meta with the relation rel into out, asking for the media type
+// of out. The relations of the links of each resource type are the Rel constants of hubapi:
+//
+//	var status hubapi.ProjectVersionPolicyStatus
+//	err := client.Follow(&version.Meta, hubapi.ProjectVersionRelPolicyStatus, &status)
+//
+// If meta has no such link, the error matches ErrLinkNotFound. Lists are better followed with FollowAll.
+func (c *Client) Follow(meta *hubapi.Meta, rel string, out interface{}) error {
+	return c.FollowContext(context.Background(), meta, rel, out)
+}
+
+func (c *Client) FollowContext(ctx context.Context, meta *hubapi.Meta, rel string, out interface{}) error {
+	link, err := findLink(meta, rel)
+	if err != nil {
+		return err
+	}
+
+	if err := c.HttpGetJSONContext(ctx, link.Href, out, http.StatusOK); err != nil {
+		return AnnotateHubClientErrorf(err, "error following the %s link of %s", rel, meta.Href)
+	}
+
+	return nil
+}
+
+// FollowAll gets all items of type T of the list at the first link of meta with the relation rel, paging
+// through the list from the offset of options with pages of its limit, 100 items if not set. The pages are
+// requested with the media type of T:
+//
+//	versions, err := hubclient.FollowAll[hubapi.ProjectVersion](ctx, client, &project.Meta, hubapi.ProjectRelVersions, nil)
+//
+// If meta has no such link, the error matches ErrLinkNotFound. To go through a long list without holding
+// all of it in memory, create an ItemStream for the link instead.
+func FollowAll[T any](ctx context.Context, c *Client, meta *hubapi.Meta, rel string, options *hubapi.GetListOptions) ([]T, error) {
+	link, err := findLink(meta, rel)
+	if err != nil {
+		return nil, err
+	}
+
+	stream := NewItemStream[T](ctx, c, link.Href, options)
+	defer stream.Close()
+
+	var items []T
+	for stream.Next() {
+		items = append(items, stream.Item())
+	}
+
+	if err := stream.Err(); err != nil {
+		return nil, AnnotateHubClientErrorf(err, "error following the %s link of %s", rel, meta.Href)
+	}
+
+	return items, nil
+}
+
+func findLink(meta *hubapi.Meta, rel string) (*hubapi.ResourceLink, error) {
+	if meta == nil {
+		return nil, AnnotateHubClientErrorf(ErrLinkNotFound, "cannot follow the %s link of a nil resource", rel)
+	}
+
+	link, err := meta.FindLinkByRel(rel)
+	if err != nil {
+		return nil, AnnotateHubClientErrorf(ErrLinkNotFound, "no %s link in %s", rel, meta.Href)
+	}
+
+	return link, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// halServer serves a project with three versions, which link to their policy status
+func halServer(t *testing.T) (*Client, map[string]string) {
+	var lock sync.Mutex
+	accepts := map[string]string{}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		accepts[r.URL.Path] = r.Header.Get(HeaderNameAccept)
+		lock.Unlock()
+
+		base := server.URL + "/api/projects/1"
+		switch {
+		case r.URL.Path == "/api/projects/1":
+			fmt.Fprintf(w, `{"name": "project", "_meta": {"href": "%s", "links": [{"rel": "versions", "href": "%s/versions"}]}}`, base, base)
+		case r.URL.Path == "/api/projects/1/versions":
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			var items []string
+			for i := offset; i < offset+limit && i < 3; i++ {
+				items = append(items, fmt.Sprintf(`{"versionName": "%d", "_meta": {"href": "%s/versions/%d", "links": [{"rel": "policy-status", "href": "%s/versions/%d/policy-status"}]}}`, i, base, i, base, i))
+			}
+			fmt.Fprintf(w, `{"totalCount": 3, "items": [%s]}`, strings.Join(items, ","))
+		case strings.HasSuffix(r.URL.Path, "/policy-status"):
+			fmt.Fprint(w, `{"overallStatus": "IN_VIOLATION"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+	return client, accepts
+}
+
+func TestFollow(t *testing.T) {
+	client, accepts := halServer(t)
+
+	project, err := client.GetProject(hubapi.ResourceLink{Href: client.BaseURL() + "/api/projects/1"})
+	require.NoError(t, err)
+
+	limit := 2
+	versions, err := FollowAll[hubapi.ProjectVersion](context.Background(), client, &project.Meta, hubapi.ProjectRelVersions, &hubapi.GetListOptions{Limit: &limit})
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	assert.Equal(t, "2", versions[2].VersionName)
+	assert.Equal(t, hubapi.ContentTypeBdProjectDetailV5, accepts["/api/projects/1/versions"])
+
+	var status hubapi.ProjectVersionPolicyStatus
+	require.NoError(t, client.Follow(&versions[1].Meta, hubapi.ProjectVersionRelPolicyStatus, &status))
+	assert.Equal(t, "IN_VIOLATION", status.OverallStatus)
+	assert.Equal(t, hubapi.ContentTypeBdBomV6, accepts["/api/projects/1/versions/1/policy-status"])
+}
+
+func TestFollowErrors(t *testing.T) {
+	client, _ := halServer(t)
+
+	project, err := client.GetProject(hubapi.ResourceLink{Href: client.BaseURL() + "/api/projects/1"})
+	require.NoError(t, err)
+
+	var list hubapi.ProjectVersionList
+	err = client.Follow(&project.Meta, hubapi.ProjectRelUsers, &list)
+	assert.True(t, errors.Is(err, ErrLinkNotFound))
+	assert.Contains(t, err.Error(), "no users link in "+project.Meta.Href)
+
+	_, err = FollowAll[hubapi.User](context.Background(), client, nil, hubapi.ProjectRelUsers, nil)
+	assert.True(t, errors.Is(err, ErrLinkNotFound))
+
+	project.Meta.Links = append(project.Meta.Links, hubapi.ResourceLink{Rel: hubapi.ProjectRelUsers, Href: client.BaseURL() + "/api/projects/1/users"})
+	_, err = FollowAll[hubapi.User](context.Background(), client, &project.Meta, hubapi.ProjectRelUsers, nil)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.False(t, errors.Is(err, ErrLinkNotFound))
+}
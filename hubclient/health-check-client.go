@@ -15,34 +15,52 @@
 package hubclient
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+
 	"github.com/blackducksoftware/hub-client-go/hubapi"
-	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 )
 
 func (c *Client) CheckHubReadiness() (error, *hubapi.HealthCheckStatus) {
+	return c.CheckHubReadinessContext(context.Background())
+}
+
+func (c *Client) CheckHubReadinessContext(ctx context.Context) (error, *hubapi.HealthCheckStatus) {
 	readinessUrl := hubapi.BuildUrl(c.BaseURL(), hubapi.ReadinessApi)
-	return checkHealthStatus(c, readinessUrl)
+	return checkHealthStatus(ctx, c, readinessUrl)
 }
 
 func (c *Client) CheckHubLiveness() (error, *hubapi.HealthCheckStatus) {
+	return c.CheckHubLivenessContext(context.Background())
+}
+
+func (c *Client) CheckHubLivenessContext(ctx context.Context) (error, *hubapi.HealthCheckStatus) {
 	livenessUrl := hubapi.BuildUrl(c.BaseURL(), hubapi.LivenessApi)
-	return checkHealthStatus(c, livenessUrl)
+	return checkHealthStatus(ctx, c, livenessUrl)
 }
 
-func checkHealthStatus(c *Client, url string) (error, *hubapi.HealthCheckStatus) {
+func checkHealthStatus(ctx context.Context, c *Client, url string) (error, *hubapi.HealthCheckStatus) {
 	var status hubapi.HealthCheckStatus
-	resp, err := c.httpClient.Get(url)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		log.Error("Error fetching hub health status", err)
-		return err, nil
+		return AnnotateHubClientError(err, "error creating health check request"), nil
 	}
 
-	if resp != nil {
-		if err := json.NewDecoder(resp.Body).Decode(&status); err == nil {
-			return nil, &status
-		}
+	c.applyHeaderValues(req, nil)
+
+	resp, err := c.do(req)
+	if err != nil {
+		c.log().Error("Error fetching hub health status", urlField(url), errorField(err))
+		return AnnotateHubClientError(err, "error fetching hub health status"), nil
+	}
+
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return HubClientStatusCodeErrorf(resp.StatusCode, "error fetching hub health status, got a %d response: %v", resp.StatusCode, err), nil
 	}
-	return errors.New("error fetching hub health status"), nil
+
+	return nil, &status
 }
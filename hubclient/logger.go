@@ -0,0 +1,179 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Keys of the fields attached to log messages
+const (
+	FieldURL      = "url"
+	FieldMethod   = "method"
+	FieldStatus   = "status"
+	FieldDuration = "duration"
+	FieldLogRef   = "logRef"
+	FieldError    = "error"
+)
+
+// Field is a key and value attached to a log message
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger receives the log messages of a Client. Messages are constant, the details are passed as fields.
+type Logger interface {
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+}
+
+// SetLogger makes the Client write its log messages to logger. A nil logger restores the default,
+// which writes to the standard logrus logger.
+func (c *Client) SetLogger(logger Logger) {
+	c.logger = logger
+}
+
+// WithLogger sets the Logger of the Client, see SetLogger
+func WithLogger(logger Logger) Option {
+	return func(o *clientOptions) error {
+		o.logger = logger
+		return nil
+	}
+}
+
+var defaultLogger = NewLogrusLogger(logrus.StandardLogger())
+
+func (c *Client) log() Logger {
+	if c.logger == nil {
+		return defaultLogger
+	}
+
+	return c.logger
+}
+
+func urlField(url string) Field {
+	return Field{FieldURL, url}
+}
+
+func methodField(method string) Field {
+	return Field{FieldMethod, method}
+}
+
+func statusField(status int) Field {
+	return Field{FieldStatus, status}
+}
+
+func durationField(d time.Duration) Field {
+	return Field{FieldDuration, d}
+}
+
+func errorField(err error) Field {
+	return Field{FieldError, err}
+}
+
+type logrusLogger struct {
+	logger logrus.FieldLogger
+}
+
+// NewLogrusLogger creates a Logger writing to a logrus logger, with the fields as logrus fields
+func NewLogrusLogger(logger logrus.FieldLogger) Logger {
+	return logrusLogger{logger}
+}
+
+func (l logrusLogger) entry(fields []Field) logrus.FieldLogger {
+	if len(fields) == 0 {
+		return l.logger
+	}
+
+	logrusFields := make(logrus.Fields, len(fields))
+	for _, f := range fields {
+		logrusFields[f.Key] = f.Value
+	}
+
+	return l.logger.WithFields(logrusFields)
+}
+
+func (l logrusLogger) Debug(msg string, fields ...Field) { l.entry(fields).Debug(msg) }
+func (l logrusLogger) Info(msg string, fields ...Field)  { l.entry(fields).Info(msg) }
+func (l logrusLogger) Warn(msg string, fields ...Field)  { l.entry(fields).Warn(msg) }
+func (l logrusLogger) Error(msg string, fields ...Field) { l.entry(fields).Error(msg) }
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger creates a Logger writing to a slog logger, with the fields as attributes
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return slogLogger{logger}
+}
+
+func (l slogLogger) log(level slog.Level, msg string, fields []Field) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, f := range fields {
+		attrs = append(attrs, slog.Any(f.Key, f.Value))
+	}
+
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+func (l slogLogger) Debug(msg string, fields ...Field) { l.log(slog.LevelDebug, msg, fields) }
+func (l slogLogger) Info(msg string, fields ...Field)  { l.log(slog.LevelInfo, msg, fields) }
+func (l slogLogger) Warn(msg string, fields ...Field)  { l.log(slog.LevelWarn, msg, fields) }
+func (l slogLogger) Error(msg string, fields ...Field) { l.log(slog.LevelError, msg, fields) }
+
+type nopLogger struct{}
+
+// NopLogger returns a Logger discarding all messages
+func NopLogger() Logger {
+	return nopLogger{}
+}
+
+func (nopLogger) Debug(string, ...Field) {}
+func (nopLogger) Info(string, ...Field)  {}
+func (nopLogger) Warn(string, ...Field)  {}
+func (nopLogger) Error(string, ...Field) {}
+
+// logResponse logs every request sent with its outcome
+func (c *Client) logResponse(req *http.Request, resp *http.Response, err error, d time.Duration) {
+	fields := []Field{methodField(req.Method), urlField(req.URL.String()), durationField(d)}
+
+	if resp != nil {
+		fields = append(fields, statusField(resp.StatusCode))
+		if resp.StatusCode >= http.StatusBadRequest {
+			if logRef := peekLogRef(resp); logRef != "" {
+				fields = append(fields, Field{FieldLogRef, logRef})
+			}
+		}
+	}
+
+	if err != nil {
+		fields = append(fields, errorField(err))
+	}
+
+	c.log().Debug("HTTP request done", fields...)
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type logEntry struct {
+	level  string
+	msg    string
+	fields map[string]interface{}
+}
+
+// recordingLogger keeps every message logged
+type recordingLogger struct {
+	lock    sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordingLogger) record(level, msg string, fields []Field) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	entry := logEntry{level: level, msg: msg, fields: map[string]interface{}{}}
+	for _, f := range fields {
+		entry.fields[f.Key] = f.Value
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func (l *recordingLogger) Debug(msg string, fields ...Field) { l.record("debug", msg, fields) }
+func (l *recordingLogger) Info(msg string, fields ...Field)  { l.record("info", msg, fields) }
+func (l *recordingLogger) Warn(msg string, fields ...Field)  { l.record("warn", msg, fields) }
+func (l *recordingLogger) Error(msg string, fields ...Field) { l.record("error", msg, fields) }
+
+func (l *recordingLogger) find(level, msg string) (logEntry, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for _, entry := range l.entries {
+		if entry.level == level && entry.msg == msg {
+			return entry, true
+		}
+	}
+	return logEntry{}, false
+}
+
+func TestLoggerReceivesStructuredFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errorMessage": "not found", "logRef": "ab12cd34"}`))
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithLogger(logger))
+	require.NoError(t, err)
+
+	link := hubapi.ResourceLink{Href: server.URL + "/api/components/1"}
+	_, err = client.GetComponent(link)
+	require.Error(t, err)
+
+	done, ok := logger.find("debug", "HTTP request done")
+	require.True(t, ok)
+	assert.Equal(t, http.MethodGet, done.fields[FieldMethod])
+	assert.Equal(t, link.Href, done.fields[FieldURL])
+	assert.Equal(t, http.StatusNotFound, done.fields[FieldStatus])
+	assert.Equal(t, "ab12cd34", done.fields[FieldLogRef])
+	assert.Contains(t, done.fields, FieldDuration)
+
+	failed, ok := logger.find("error", "Error trying to retrieve a component")
+	require.True(t, ok)
+	assert.Equal(t, link.Href, failed.fields[FieldURL])
+	assert.Error(t, failed.fields[FieldError].(error))
+}
+
+func TestLoggerReplacesGlobalLogrus(t *testing.T) {
+	var global bytes.Buffer
+	output, level := logrus.StandardLogger().Out, logrus.GetLevel()
+	logrus.SetOutput(&global)
+	logrus.SetLevel(logrus.DebugLevel)
+	defer func() {
+		logrus.SetOutput(output)
+		logrus.SetLevel(level)
+	}()
+
+	client, err := New("http://localhost:1", WithLogger(NopLogger()))
+	require.NoError(t, err)
+
+	_, err = client.GetComponent(hubapi.ResourceLink{Href: "http://localhost:1/api/components/1"})
+	require.Error(t, err)
+	assert.Empty(t, global.String())
+}
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	logger.Warn("Did not get a location header back", urlField("https://blackduck/api/projects"), statusField(201))
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "Did not get a location header back", record["msg"])
+	assert.Equal(t, "https://blackduck/api/projects", record[FieldURL])
+	assert.Equal(t, 201.0, record[FieldStatus])
+}
+
+func TestLogrusLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := logrus.New()
+	l.SetOutput(&buf)
+	l.SetFormatter(&logrus.JSONFormatter{})
+
+	NewLogrusLogger(l).Error("Error uploading bdio files", urlField("https://blackduck/api/developer-scans/1"))
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "error", record["level"])
+	assert.Equal(t, "Error uploading bdio files", record["msg"])
+	assert.Equal(t, "https://blackduck/api/developer-scans/1", record[FieldURL])
+}
@@ -15,12 +15,21 @@
 package hubclient
 
 import (
-	"github.com/blackducksoftware/hub-client-go/hubapi"
-	log "github.com/sirupsen/logrus"
+	"context"
+	"net/http"
 	"net/url"
+	"strings"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
 )
 
 func (c *Client) Login(username string, password string) error {
+	return c.LoginContext(context.Background(), username, password)
+}
+
+// LoginContext logs in with the form login. If session re-login is enabled (see SetSessionRelogin),
+// the credentials are kept to log in again when the session expires.
+func (c *Client) LoginContext(ctx context.Context, username string, password string) error {
 	loginURL := hubapi.BuildUrl(c.baseURL, hubapi.SecurityApi)
 
 	formValues := url.Values{
@@ -28,22 +37,135 @@ func (c *Client) Login(username string, password string) error {
 		"j_password": {password},
 	}
 
-	resp, err := c.httpClient.PostForm(loginURL, formValues)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, loginURL, strings.NewReader(formValues.Encode()))
+	if err != nil {
+		return AnnotateHubClientError(err, "Error creating form login request")
+	}
+
+	req.Header.Set(HeaderNameContentType, "application/x-www-form-urlencoded")
+
+	resp, err := c.do(req)
 
 	if err != nil {
 		return AnnotateHubClientError(err, "Error trying to login via form login")
 	}
 
+	defer resp.Body.Close()
+
 	if resp.StatusCode != 204 {
 		return HubClientStatusCodeErrorf(resp.StatusCode, "got a %d response instead of a 204", resp.StatusCode)
 	}
 
+	c.authLock.Lock()
 	if csrf := resp.Header.Get(HeaderNameCsrfToken); csrf != "" {
 		c.haveCsrfToken = true
 		c.csrfToken = csrf
 	}
+	if c.sessionRelogin {
+		c.username = username
+		c.password = password
+	}
+	c.sessionUser = username
+	c.sessionGeneration++
+	c.authLock.Unlock()
 
-	log.Debugln("Login: Successfully authenticated")
+	c.log().Debug("Login: Successfully authenticated")
 
 	return nil
 }
+
+// SetSessionRelogin enables or disables logging in again when the session of a Client created with
+// NewWithSession expires. It must be enabled before calling Login, which then keeps the credentials in memory.
+// A request which fails because of an expired session is retried once after logging in again.
+func (c *Client) SetSessionRelogin(enabled bool) {
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
+
+	c.sessionRelogin = enabled
+	if !enabled {
+		c.username = ""
+		c.password = ""
+	}
+}
+
+// canRelogin reports whether the request may be retried after logging in again
+func (c *Client) canRelogin(req *http.Request) bool {
+	c.authLock.RLock()
+	defer c.authLock.RUnlock()
+
+	return c.sessionRelogin && c.username != "" && !strings.HasSuffix(req.URL.Path, hubapi.SecurityApi)
+}
+
+func (c *Client) currentSession() uint64 {
+	c.authLock.RLock()
+	defer c.authLock.RUnlock()
+	return c.sessionGeneration
+}
+
+// sessionExpired reports whether the response shows that the session is no longer valid:
+// either the server answered with a 401, or it redirected the request to the login page
+func sessionExpired(req *http.Request, resp *http.Response) bool {
+	if resp.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+
+	if resp.Request != nil && resp.Request.URL.Path != req.URL.Path {
+		return strings.Contains(strings.ToLower(resp.Request.URL.Path), "login")
+	}
+
+	return false
+}
+
+// relogin logs in again with the stored credentials unless another goroutine already did so
+// since staleSession was current
+func (c *Client) relogin(ctx context.Context, staleSession uint64) error {
+	c.refreshLock.Lock()
+	defer c.refreshLock.Unlock()
+
+	if c.currentSession() != staleSession {
+		return nil
+	}
+
+	c.authLock.RLock()
+	username, password := c.username, c.password
+	c.authLock.RUnlock()
+
+	c.log().Debug("Session expired, logging in again", Field{"username", username})
+	c.observeAuthRefresh(hubapi.SecurityApi)
+
+	return AnnotateHubClientErrorf(c.LoginContext(ctx, username, password), "session expired and logging in again as %s failed", username)
+}
+
+// doWithRelogin sends a session authenticated request, logging in again and retrying it once
+// if the session has expired
+func (c *Client) doWithRelogin(req *http.Request) (*http.Response, error) {
+	session := c.currentSession()
+
+	resp, err := c.send(req)
+	if err != nil || !sessionExpired(req, resp) {
+		return resp, err
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// the body is gone, let the caller deal with the expired session
+		return resp, nil
+	}
+
+	discardResponse(resp)
+
+	if err := c.relogin(req.Context(), session); err != nil {
+		return nil, err
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, AnnotateHubClientError(err, "unable to replay request body")
+		}
+	}
+
+	// pick up the new CSRF token
+	c.setAuthHeaders(retry)
+
+	return c.send(retry)
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sessionServer hands out a new session cookie and CSRF token on every login
+type sessionServer struct {
+	lock     sync.Mutex
+	password string
+	logins   int
+	session  string
+}
+
+func (s *sessionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if r.URL.Path == hubapi.SecurityApi {
+		if r.FormValue("j_username") != "sysadmin" || r.FormValue("j_password") != s.password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		s.logins++
+		s.session = fmt.Sprintf("session-%d", s.logins)
+		http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: s.session, Path: "/"})
+		w.Header().Set(HeaderNameCsrfToken, "csrf-"+s.session)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	cookie, err := r.Cookie("JSESSIONID")
+	if err != nil || cookie.Value != s.session || r.Header.Get(HeaderNameCsrfToken) != "csrf-"+s.session {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	w.Write([]byte(`{"version": "2024.1.0"}`))
+}
+
+func (s *sessionServer) expireSession() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.session = "expired"
+}
+
+func newSessionServerClient(t *testing.T, ss *sessionServer, relogin bool) (*Client, *httptest.Server) {
+	server := httptest.NewServer(ss)
+	t.Cleanup(server.Close)
+
+	client, err := NewWithClient(server.URL, 0, server.Client())
+	require.NoError(t, err)
+	client.SetSessionRelogin(relogin)
+	require.NoError(t, client.Login("sysadmin", ss.password))
+
+	return client, server
+}
+
+func TestClient_LogsInAgainWhenSessionExpires(t *testing.T) {
+	ss := &sessionServer{password: "blackduck"}
+	client, server := newSessionServerClient(t, ss, true)
+
+	ss.expireSession()
+
+	var version hubapi.CurrentVersion
+	err := client.HttpGetJSON(server.URL+hubapi.CurrentVersionApi, &version, http.StatusOK)
+
+	require.NoError(t, err)
+	assert.Equal(t, "2024.1.0", version.Version)
+	assert.Equal(t, 2, ss.logins)
+	assert.Equal(t, "csrf-session-2", client.csrfToken)
+}
+
+func TestClient_DoesNotLogInAgainUnlessEnabled(t *testing.T) {
+	ss := &sessionServer{password: "blackduck"}
+	client, server := newSessionServerClient(t, ss, false)
+
+	ss.expireSession()
+
+	err := client.HttpGetJSON(server.URL+hubapi.CurrentVersionApi, &hubapi.CurrentVersion{}, http.StatusOK)
+
+	require.Error(t, err)
+	assert.Equal(t, 1, ss.logins)
+	assert.Empty(t, client.password)
+}
+
+func TestClient_ReportsFailedRelogin(t *testing.T) {
+	ss := &sessionServer{password: "blackduck"}
+	client, server := newSessionServerClient(t, ss, true)
+
+	ss.expireSession()
+	ss.password = "changed"
+
+	err := client.HttpGetJSON(server.URL+hubapi.CurrentVersionApi, &hubapi.CurrentVersion{}, http.StatusOK)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "session expired and logging in again as sysadmin failed")
+	assert.Equal(t, http.StatusUnauthorized, err.(*HubClientError).StatusCode)
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Metrics receives the measurements of the traffic of the Clients using it, see SetMetrics. Every request
+// sent to the server is observed, including every attempt of a retried request, but not the responses
+// served from the cache. Requests are identified by their HTTP method and route, such as
+// /api/projects/{id}/versions. Implementations must be safe for concurrent use. The prommetrics package
+// reports them to Prometheus.
+type Metrics interface {
+	// ObserveRequest is called once the response headers of a request were received, or it failed. The
+	// statusCode is 0 if no response was received, sentBytes the size of the request body if it is known.
+	ObserveRequest(method, route string, statusCode int, duration time.Duration, sentBytes int64)
+	// ObserveReceivedBytes is called with the number of bytes read from a response body, as they are read
+	ObserveReceivedBytes(method, route string, n int)
+	// ObservePoll is called every time the results of a rapid scan are polled
+	ObservePoll(route string)
+	// ObserveAuthRefresh is called every time an expired bearer token or session is renewed, with the route
+	// of the authentication
+	ObserveAuthRefresh(route string)
+}
+
+// SetMetrics makes the Client report its traffic to metrics, which may be shared by several Clients.
+// Nil disables the metrics.
+func (c *Client) SetMetrics(metrics Metrics) {
+	c.metrics = metrics
+}
+
+// WithMetrics makes the Client report its traffic to metrics, see SetMetrics
+func WithMetrics(metrics Metrics) Option {
+	return func(o *clientOptions) error {
+		o.metrics = metrics
+		return nil
+	}
+}
+
+// transmit sends a single request through the underlying http.Client, measuring it
+func (c *Client) transmit(req *http.Request) (*http.Response, error) {
+	acceptCompression(req)
+
+	m := c.metrics
+	if m == nil {
+		resp, err := c.httpClient.Do(req)
+		return c.limitResponse(req, decompressResponse(resp)), err
+	}
+
+	method, route := req.Method, routeTemplate(req.URL.Path)
+	sentBytes := req.ContentLength
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	m.ObserveRequest(method, route, getResponseStatus(resp), time.Since(start), sentBytes)
+
+	if resp != nil && resp.Body != nil {
+		resp.Body = &countingReadCloser{ReadCloser: resp.Body, count: func(n int) { m.ObserveReceivedBytes(method, route, n) }}
+	}
+
+	return c.limitResponse(req, decompressResponse(resp)), err
+}
+
+// countingReadCloser counts the bytes read from a response body
+type countingReadCloser struct {
+	io.ReadCloser
+	count func(n int)
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.count(n)
+	}
+	return n, err
+}
+
+func (c *Client) observePoll(rapidScanEndpoint string) {
+	if c.metrics == nil {
+		return
+	}
+
+	route := rapidScanEndpoint
+	if u, err := url.Parse(rapidScanEndpoint); err == nil {
+		route = routeTemplate(u.Path)
+	}
+
+	c.metrics.ObservePoll(route)
+}
+
+func (c *Client) observeAuthRefresh(route string) {
+	if c.metrics != nil {
+		c.metrics.ObserveAuthRefresh(route)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingMetrics records what it observes, by method and route
+type recordingMetrics struct {
+	lock          sync.Mutex
+	requests      []string
+	sentBytes     map[string]int64
+	receivedBytes map[string]int
+	polls         []string
+	authRefreshes []string
+}
+
+func (m *recordingMetrics) ObserveRequest(method, route string, statusCode int, duration time.Duration, sentBytes int64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.requests = append(m.requests, fmt.Sprintf("%s %s %d", method, route, statusCode))
+	if sentBytes > 0 {
+		if m.sentBytes == nil {
+			m.sentBytes = map[string]int64{}
+		}
+		m.sentBytes[method+" "+route] += sentBytes
+	}
+}
+
+func (m *recordingMetrics) ObserveReceivedBytes(method, route string, n int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.receivedBytes == nil {
+		m.receivedBytes = map[string]int{}
+	}
+	m.receivedBytes[method+" "+route] += n
+}
+
+func (m *recordingMetrics) ObservePoll(route string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.polls = append(m.polls, route)
+}
+
+func (m *recordingMetrics) ObserveAuthRefresh(route string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.authRefreshes = append(m.authRefreshes, route)
+}
+
+func TestMetricsObserveRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/projects/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"name": "project"}`))
+	}))
+	defer server.Close()
+
+	metrics := &recordingMetrics{}
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithMetrics(metrics))
+	require.NoError(t, err)
+
+	for _, id := range []string{"7d5a4b0e-1b8a-4b4e-9a8e-6f0c3c1d2e11", "0c8c9d73-6f9b-4e67-a3f4-0c2e7c6c1a11"} {
+		_, err := client.GetProject(hubapi.ResourceLink{Href: server.URL + "/api/projects/" + id})
+		require.NoError(t, err)
+	}
+	_, err = client.GetProject(hubapi.ResourceLink{Href: server.URL + "/api/projects/missing"})
+	require.Error(t, err)
+
+	assert.Equal(t, []string{"GET /api/projects/{id} 200", "GET /api/projects/{id} 200", "GET /api/projects/missing 404"}, metrics.requests)
+	assert.Equal(t, 2*len(`{"name": "project"}`), metrics.receivedBytes["GET /api/projects/{id}"])
+}
+
+func TestMetricsObserveAuthRefreshes(t *testing.T) {
+	ts := &tokenServer{expiresInMs: time.Hour.Milliseconds()}
+	client, server := newTokenServerClient(t, ts)
+
+	metrics := &recordingMetrics{}
+	client.SetMetrics(metrics)
+	ts.expireToken()
+
+	var version hubapi.CurrentVersion
+	_, err := client.HttpPostJSONExpectResult(server.URL, "payload", &version, "application/json", http.StatusOK)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{hubapi.AuthenticateApi}, metrics.authRefreshes)
+	assert.Equal(t, []string{"POST  401", "POST " + hubapi.AuthenticateApi + " 200", "POST  200"}, metrics.requests)
+	assert.Equal(t, int64(2*(len(`"payload"`)+1)), metrics.sentBytes["POST "])
+}
+
+func TestMetricsObserveRapidScanPolls(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls < 3 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"totalCount": 0, "items": []}`))
+	}))
+	defer server.Close()
+
+	metrics := &recordingMetrics{}
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithMetrics(metrics))
+	require.NoError(t, err)
+
+	err, _ = client.PollRapidScanResults(server.URL+"/api/developer-scans/1", time.Millisecond, time.Second, 100, RapidScanOpts{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/api/developer-scans/{id}", "/api/developer-scans/{id}", "/api/developer-scans/{id}"}, metrics.polls)
+	assert.Len(t, metrics.requests, 3)
+}
@@ -15,16 +15,21 @@
 package hubclient
 
 import (
+	"context"
 	"fmt"
 	"github.com/blackducksoftware/hub-client-go/hubapi"
 )
 
 func (c *Client) MfaStatus() (*hubapi.MfaStatus, error) {
+	return c.MfaStatusContext(context.Background())
+}
+
+func (c *Client) MfaStatusContext(ctx context.Context) (*hubapi.MfaStatus, error) {
 
 	mfaStatusURL := hubapi.BuildUrl(c.baseURL, hubapi.MfaStatusApi)
 
 	var mfaStatus hubapi.MfaStatus
-	err := c.HttpGetJSON(mfaStatusURL, &mfaStatus, 200)
+	err := c.HttpGetJSONContext(ctx, mfaStatusURL, &mfaStatus, 200)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, fmt.Sprintf("Error trying to get MFA status"))
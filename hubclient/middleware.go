@@ -0,0 +1,52 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"net/http"
+)
+
+// DoFunc sends a request and returns its response
+type DoFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the sending of every request made by the Client, including logins, token authentication,
+// health checks and downloads. It may change the request before passing it on to next, inspect or replace
+// the response, or return a response of its own without calling next at all.
+// The request is passed to the middleware before re-authentication and retries, so it sees each
+// request once along with its final response. Responses served from the cache pass through the middlewares too.
+type Middleware func(next DoFunc) DoFunc
+
+// Use appends middlewares to the chain of the Client. The first middleware added is the outermost one,
+// seeing the request first and the response last. Use must not be called while requests are in flight.
+func (c *Client) Use(middlewares ...Middleware) {
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+// WithMiddleware adds middlewares to the Client, see Client.Use
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(o *clientOptions) error {
+		o.middlewares = append(o.middlewares, middlewares...)
+		return nil
+	}
+}
+
+// chain wraps the given function with the middlewares of the Client
+func (c *Client) chain(do DoFunc) DoFunc {
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		do = c.middlewares[i](do)
+	}
+
+	return do
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(next DoFunc) DoFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			*calls = append(*calls, name+" "+req.Method+" "+req.URL.Path)
+			resp, err := next(req)
+			*calls = append(*calls, name+" done")
+			return resp, err
+		}
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "outer", r.Header.Get("X-Outer"))
+		w.Write([]byte(`{"version": "2024.1.0"}`))
+	}))
+	defer server.Close()
+
+	var calls []string
+	setHeader := func(next DoFunc) DoFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Outer", "outer")
+			return next(req)
+		}
+	}
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithMiddleware(setHeader, recordingMiddleware("first", &calls)))
+	require.NoError(t, err)
+	client.Use(recordingMiddleware("second", &calls))
+
+	_, err = client.CurrentVersion()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"first GET " + hubapi.CurrentVersionApi,
+		"second GET " + hubapi.CurrentVersionApi,
+		"second done",
+		"first done",
+	}, calls)
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	client, err := New("https://localhost", WithMiddleware(func(next DoFunc) DoFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"version": "cached"}`)),
+				Request:    req,
+			}, nil
+		}
+	}))
+	require.NoError(t, err)
+
+	version, err := client.CurrentVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "cached", version.Version)
+}
+
+func TestMiddlewareInspectsResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var statuses []int
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithMiddleware(func(next DoFunc) DoFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			resp, err := next(req)
+			if resp != nil {
+				statuses = append(statuses, resp.StatusCode)
+			}
+			return resp, err
+		}
+	}))
+	require.NoError(t, err)
+
+	_, err = client.CurrentVersion()
+	assert.Error(t, err)
+	assert.Equal(t, []int{http.StatusServiceUnavailable}, statuses)
+}
+
+func TestMiddlewareSeesAuthentication(t *testing.T) {
+	ss := &sessionServer{password: "blackduck"}
+	server := httptest.NewServer(ss)
+	defer server.Close()
+
+	var calls []string
+	client, err := New(server.URL,
+		WithHTTPClient(server.Client()),
+		WithMiddleware(recordingMiddleware("mw", &calls)),
+		WithCredentials(UsernamePasswordCredentials{Username: "sysadmin", Password: ss.password}))
+	require.NoError(t, err)
+
+	_, err = client.CurrentVersion()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"mw POST " + hubapi.SecurityApi,
+		"mw done",
+		"mw GET " + hubapi.CurrentVersionApi,
+		"mw done",
+	}, calls)
+}
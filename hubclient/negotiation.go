@@ -0,0 +1,376 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+)
+
+// SetMediaTypeNegotiation makes the Client ask for the media types best suited to the Black Duck release it
+// talks to, instead of the ones built into the hubapi structs. The release is read from /api/current-version
+// on the first request for a media type of hubapi.MediaTypeVersions, and the newest version of each resource
+// the release supports is asked for, in Accept as well as in the Content-Type of what is sent. Should the
+// server answer 406 Not Acceptable or 415 Unsupported Media Type, the request is sent again with the next
+// older version, which is used for the same endpoint from then on.
+// See ServerVersion and NegotiatedMediaType for the outcome.
+func (c *Client) SetMediaTypeNegotiation(enabled bool) {
+	if enabled {
+		c.negotiation = &negotiation{}
+	} else {
+		c.negotiation = nil
+	}
+}
+
+// WithMediaTypeNegotiation enables media type negotiation, see SetMediaTypeNegotiation
+func WithMediaTypeNegotiation() Option {
+	return func(o *clientOptions) error {
+		o.mediaTypeNegotiation = true
+		return nil
+	}
+}
+
+// ServerVersion returns the Black Duck release found by media type negotiation, empty if it has not been
+// read yet or negotiation is disabled
+func (c *Client) ServerVersion() string {
+	n := c.negotiation
+	if n == nil {
+		return ""
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.serverVersion
+}
+
+// NegotiatedMediaType returns the media type the Client asks for in place of mediaType at href, such as
+// application/vnd.blackducksoftware.bill-of-materials-5+json for the bill-of-materials-6 of BomComponent
+// on an older server. It is mediaType itself while nothing has been negotiated for it.
+func (c *Client) NegotiatedMediaType(href string, mediaType string) string {
+	n := c.negotiation
+	if n == nil {
+		return mediaType
+	}
+
+	path := href
+	if u, err := url.Parse(href); err == nil {
+		path = u.Path
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.replace(endpointOf(path), mediaType)
+}
+
+type negotiation struct {
+	// lookup lets one request at a time look up the server version, lock is not held meanwhile
+	lookup sync.Mutex
+
+	lock sync.Mutex
+	// checked is set once the server version has been looked up, successfully or not
+	checked       bool
+	serverVersion string
+	// fallbacks are the media types asked for after the server refused newer ones
+	fallbacks map[negotiated]string
+}
+
+// negotiated identifies a media type built into the hubapi structs as asked for at an endpoint
+type negotiated struct {
+	endpoint  string
+	mediaType string
+}
+
+// negotiating wraps next to negotiate the media types of the requests it sends
+func (c *Client) negotiating(next DoFunc) DoFunc {
+	n := c.negotiation
+	if n == nil {
+		return next
+	}
+
+	return func(req *http.Request) (*http.Response, error) {
+		// this includes the lookup of the server version, which is plain JSON
+		if !hasVersionedMediaType(req) {
+			return next(req)
+		}
+
+		c.lookUpServerVersion(req.Context(), n)
+
+		endpoint := endpointOf(req.URL.Path)
+		for {
+			rewritten := n.rewrite(endpoint, req)
+
+			resp, err := next(rewritten)
+			if err != nil || !isMediaTypeRefusal(resp.StatusCode) || !canResend(req) {
+				return resp, err
+			}
+
+			if !n.fallBack(endpoint, mediaTypesOf(req), mediaTypesOf(rewritten)) {
+				return resp, err
+			}
+
+			c.log().Info("Falling back to an older media type", urlField(req.URL.String()), Field{"mediaTypes", strings.Join(mediaTypesOf(rewritten), ", ")})
+
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+
+			if req, err = resend(req); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// lookUpServerVersion reads the server version to pick the media types for, once per Client
+func (c *Client) lookUpServerVersion(ctx context.Context, n *negotiation) {
+	n.lookup.Lock()
+	defer n.lookup.Unlock()
+
+	n.lock.Lock()
+	checked := n.checked
+	n.lock.Unlock()
+	if checked {
+		return
+	}
+
+	version, err := c.CurrentVersionContext(ctx)
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if err != nil {
+		// try again with the next request if the caller gave up, otherwise stick to the built in media types
+		n.checked = ctx.Err() == nil
+		c.log().Warn("Unable to read the server version for media type negotiation", errorField(err))
+		return
+	}
+
+	n.checked = true
+	n.serverVersion = version.Version
+
+	c.log().Debug("Negotiated media types", Field{"serverVersion", version.Version})
+}
+
+// rewrite returns req asking for and sending the media types chosen for endpoint
+func (n *negotiation) rewrite(endpoint string, req *http.Request) *http.Request {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	accept := req.Header.Values(HeaderNameAccept)
+	rewritten := make([]string, len(accept))
+	changed := false
+	for i, mediaType := range accept {
+		rewritten[i] = n.replace(endpoint, mediaType)
+		changed = changed || rewritten[i] != mediaType
+	}
+
+	contentType := req.Header.Get(HeaderNameContentType)
+	chosenContentType := n.replace(endpoint, contentType)
+
+	if !changed && chosenContentType == contentType {
+		return req
+	}
+
+	req = req.Clone(req.Context())
+	if changed {
+		req.Header[HeaderNameAccept] = rewritten
+	}
+	if chosenContentType != contentType {
+		req.Header.Set(HeaderNameContentType, chosenContentType)
+	}
+	return req
+}
+
+// replace returns the media type to use in place of mediaType at endpoint: the one fallen back to, if any,
+// otherwise the newest version the server supports
+func (n *negotiation) replace(endpoint string, mediaType string) string {
+	versions, ok := hubapi.MediaTypeVersions[mediaType]
+	if !ok {
+		return mediaType
+	}
+
+	if fallback, ok := n.fallbacks[negotiated{endpoint, mediaType}]; ok {
+		return fallback
+	}
+
+	if n.serverVersion == "" {
+		return mediaType
+	}
+
+	chosen := mediaType
+	for _, v := range versions {
+		if compareVersions(v.Since, n.serverVersion) <= 0 {
+			chosen = v.MediaType
+		}
+	}
+	return chosen
+}
+
+// fallBack chooses, for endpoint, the versions preceding the ones refused by the server in place of the
+// media types built into the hubapi structs, refused[i] being the one asked for in place of mediaTypes[i].
+// It reports whether there is an older version of any of them.
+func (n *negotiation) fallBack(endpoint string, mediaTypes []string, refused []string) bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.fallbacks == nil {
+		n.fallbacks = make(map[negotiated]string)
+	}
+
+	found := false
+	for i, mediaType := range mediaTypes {
+		versions := hubapi.MediaTypeVersions[mediaType]
+		if versions == nil {
+			continue
+		}
+
+		// another request refused the same version may have fallen back already
+		if n.replace(endpoint, mediaType) != refused[i] {
+			found = true
+			continue
+		}
+
+		for v := len(versions) - 1; v > 0; v-- {
+			if versions[v].MediaType == refused[i] {
+				n.fallbacks[negotiated{endpoint, mediaType}] = versions[v-1].MediaType
+				found = true
+				break
+			}
+		}
+	}
+
+	return found
+}
+
+// mediaTypesOf returns the media types req asks for and sends
+func mediaTypesOf(req *http.Request) []string {
+	mediaTypes := req.Header.Values(HeaderNameAccept)
+	if contentType := req.Header.Get(HeaderNameContentType); contentType != "" {
+		mediaTypes = append(mediaTypes[:len(mediaTypes):len(mediaTypes)], contentType)
+	}
+	return mediaTypes
+}
+
+func hasVersionedMediaType(req *http.Request) bool {
+	for _, mediaType := range mediaTypesOf(req) {
+		if hubapi.MediaTypeVersions[mediaType] != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isMediaTypeRefusal(statusCode int) bool {
+	return statusCode == http.StatusNotAcceptable || statusCode == http.StatusUnsupportedMediaType
+}
+
+// endpointOf returns path with the ids of resources in it replaced by {id}, so that
+// /api/projects/1/versions/2 and /api/projects/3/versions/4 are the same endpoint
+func endpointOf(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isResourceID(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isResourceID reports whether a path segment is the id of a resource, a number or a UUID
+func isResourceID(segment string) bool {
+	digits := false
+	for _, r := range segment {
+		switch {
+		case r >= '0' && r <= '9':
+			digits = true
+		case r >= 'a' && r <= 'f', r >= 'A' && r <= 'F', r == '-':
+		default:
+			return false
+		}
+	}
+	return digits
+}
+
+func canResend(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// resend returns a copy of req which can be sent again
+func resend(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return clone, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone.Body = body
+
+	return clone, nil
+}
+
+// compareVersions compares Black Duck releases such as 2023.10.1 by their numbers, missing numbers count
+// as 0. An empty version is older than any other.
+func compareVersions(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return -1
+	case b == "":
+		return 1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := versionNumber(as, i), versionNumber(bs, i)
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// versionNumber returns the leading number of the ith part of a version
+func versionNumber(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+
+	digits := strings.IndexFunc(parts[i], func(r rune) bool { return r < '0' || r > '9' })
+	if digits < 0 {
+		digits = len(parts[i])
+	}
+
+	n, err := strconv.Atoi(parts[i][:digits])
+	if err != nil {
+		return 0
+	}
+	return n
+}
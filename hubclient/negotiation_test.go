@@ -0,0 +1,256 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// versionedServer reports version and refuses the requests refuses matches with 406. It returns the
+// Accept headers of the requests it receives, and the Content-Type of those sending something.
+func versionedServer(t *testing.T, version string, refuses func(r *http.Request) bool) (*httptest.Server, func() []string) {
+	var lock sync.Mutex
+	var accepts []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		if r.Method == http.MethodGet {
+			accepts = append(accepts, r.URL.Path+" "+r.Header.Get(HeaderNameAccept))
+		} else {
+			accepts = append(accepts, r.Method+" "+r.URL.Path+" "+r.Header.Get(HeaderNameContentType))
+		}
+		lock.Unlock()
+
+		if r.URL.Path == hubapi.CurrentVersionApi {
+			w.Write([]byte(`{"version": "` + version + `"}`))
+			return
+		}
+
+		if refuses != nil && refuses(r) {
+			w.WriteHeader(http.StatusNotAcceptable)
+			w.Write([]byte(`{"errorCode": "{central.media_type_not_acceptable}"}`))
+			return
+		}
+
+		w.Write([]byte(`{"totalCount": 0, "items": []}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]string(nil), accepts...)
+	}
+}
+
+// refusing refuses the requests asking for one of mediaTypes
+func refusing(mediaTypes ...string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		for _, mediaType := range mediaTypes {
+			if r.Header.Get(HeaderNameAccept) == mediaType {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestMediaTypeNegotiationByServerVersion(t *testing.T) {
+	server, accepts := versionedServer(t, "2019.4.2", nil)
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithMediaTypeNegotiation())
+	require.NoError(t, err)
+	assert.Empty(t, client.ServerVersion())
+
+	link := hubapi.ResourceLink{Href: server.URL + "/api/projects/1/versions/1/components"}
+	_, err = client.ListProjectVersionComponents(link)
+	require.NoError(t, err)
+	_, err = client.ListProjects(nil)
+	require.NoError(t, err)
+
+	bomV5 := hubapi.MediaType("bill-of-materials", 5)
+	assert.Equal(t, []string{
+		hubapi.CurrentVersionApi + " ",
+		"/api/projects/1/versions/1/components " + bomV5,
+		hubapi.ProjectsApi + " " + hubapi.ContentTypeBdProjectDetailV4,
+	}, accepts(), "a project stays project-detail-4, project-detail-5 is a project version")
+
+	assert.Equal(t, "2019.4.2", client.ServerVersion())
+	assert.Equal(t, bomV5, client.NegotiatedMediaType(link.Href, hubapi.ContentTypeBdBomV6))
+	assert.Equal(t, hubapi.ContentTypeRapidScanResults, client.NegotiatedMediaType(link.Href, hubapi.ContentTypeRapidScanResults))
+}
+
+func TestMediaTypeNegotiationFallsBack(t *testing.T) {
+	components := "/api/projects/1/versions/1/components"
+	server, accepts := versionedServer(t, "2023.10.0", func(r *http.Request) bool {
+		return r.URL.Path == components && refusing(hubapi.ContentTypeBdBomV6, hubapi.MediaType("bill-of-materials", 5))(r)
+	})
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithMediaTypeNegotiation())
+	require.NoError(t, err)
+
+	link := hubapi.ResourceLink{Href: server.URL + components}
+	_, err = client.ListProjectVersionComponents(link)
+	require.NoError(t, err)
+	_, err = client.ListProjectVersionComponents(link)
+	require.NoError(t, err)
+
+	bomV4 := hubapi.MediaType("bill-of-materials", 4)
+	assert.Equal(t, []string{
+		hubapi.CurrentVersionApi + " ",
+		components + " " + hubapi.ContentTypeBdBomV6,
+		components + " " + hubapi.MediaType("bill-of-materials", 5),
+		components + " " + bomV4,
+		components + " " + bomV4,
+	}, accepts())
+	assert.Equal(t, bomV4, client.NegotiatedMediaType(link.Href, hubapi.ContentTypeBdBomV6))
+	assert.Equal(t, bomV4, client.NegotiatedMediaType(server.URL+"/api/projects/2/versions/3/components", hubapi.ContentTypeBdBomV6),
+		"the fallback holds for the other project versions")
+
+	vulnerable := hubapi.ResourceLink{Href: server.URL + "/api/projects/1/versions/1/vulnerable-bom-components"}
+	assert.Equal(t, hubapi.ContentTypeBdBomV6, client.NegotiatedMediaType(vulnerable.Href, hubapi.ContentTypeBdBomV6),
+		"the refusal of one resource does not downgrade the others of the family")
+}
+
+func TestMediaTypeNegotiationFallsBackOnceForConcurrentRefusals(t *testing.T) {
+	var refusals sync.WaitGroup
+	refusals.Add(2)
+	server, accepts := versionedServer(t, "2023.10.0", func(r *http.Request) bool {
+		if r.Header.Get(HeaderNameAccept) != hubapi.ContentTypeBdBomV6 {
+			return false
+		}
+		// both requests are refused before either of them falls back
+		refusals.Done()
+		refusals.Wait()
+		return true
+	})
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithMediaTypeNegotiation())
+	require.NoError(t, err)
+
+	link := hubapi.ResourceLink{Href: server.URL + "/api/projects/1/versions/1/components"}
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.ListProjectVersionComponents(link)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	bomV5 := hubapi.MediaType("bill-of-materials", 5)
+	assert.Equal(t, bomV5, client.NegotiatedMediaType(link.Href, hubapi.ContentTypeBdBomV6))
+	assert.NotContains(t, accepts(), "/api/projects/1/versions/1/components "+hubapi.MediaType("bill-of-materials", 4))
+}
+
+func TestMediaTypeNegotiationOfWhatIsSent(t *testing.T) {
+	server, accepts := versionedServer(t, "2019.1.0", nil)
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithMediaTypeNegotiation())
+	require.NoError(t, err)
+
+	version := "/api/projects/1/versions/1"
+	nickname := "first"
+	_, err = client.UpdateProjectVersion(hubapi.ResourceLink{Href: server.URL + version}, &hubapi.ProjectVersionUpdate{Nickname: &nickname})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		hubapi.CurrentVersionApi + " ",
+		version + " " + hubapi.ContentTypeBdProjectDetailV4,
+		"PUT " + version + " " + hubapi.ContentTypeBdProjectDetailV4,
+		version + " " + hubapi.ContentTypeBdProjectDetailV4,
+	}, accepts(), "the project version is put back in the representation it was read in")
+}
+
+func TestMediaTypeNegotiationLooksUpTheServerVersionWithoutBlocking(t *testing.T) {
+	lookingUp, release := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == hubapi.CurrentVersionApi {
+			close(lookingUp)
+			<-release
+			w.Write([]byte(`{"version": "2019.4.2"}`))
+			return
+		}
+		w.Write([]byte(`{"totalCount": 0, "items": []}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithMediaTypeNegotiation())
+	require.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		_, err := client.ListProjects(nil)
+		done <- err
+	}()
+	<-lookingUp
+
+	answered := make(chan string)
+	go func() {
+		answered <- client.ServerVersion() + client.NegotiatedMediaType(server.URL+"/api/projects", hubapi.ContentTypeBdProjectDetailV4)
+	}()
+	select {
+	case answer := <-answered:
+		assert.Equal(t, hubapi.ContentTypeBdProjectDetailV4, answer)
+	case <-time.After(5 * time.Second):
+		t.Error("the lookup of the server version blocks the negotiation state")
+	}
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.Equal(t, "2019.4.2", client.ServerVersion())
+}
+
+func TestMediaTypeNegotiationGivesUp(t *testing.T) {
+	server, accepts := versionedServer(t, "2023.10.0", refusing(hubapi.ContentTypeBdVulnerabilityV4))
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithMediaTypeNegotiation())
+	require.NoError(t, err)
+
+	_, err = client.GetVulnerability(hubapi.ResourceLink{Href: server.URL + "/api/vulnerabilities/1"})
+	assert.Equal(t, http.StatusNotAcceptable, err.(*HubClientError).StatusCode)
+	assert.Len(t, accepts(), 2)
+}
+
+func TestMediaTypeNegotiationIsOptional(t *testing.T) {
+	server, accepts := versionedServer(t, "2019.4.2", refusing(hubapi.ContentTypeBdBomV6))
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+
+	_, err = client.ListProjectVersionComponents(hubapi.ResourceLink{Href: server.URL + "/api/projects/1/versions/1/components"})
+	require.Error(t, err)
+	assert.Len(t, accepts(), 1)
+	assert.Empty(t, client.ServerVersion())
+	assert.Equal(t, hubapi.ContentTypeBdBomV6, client.NegotiatedMediaType(server.URL, hubapi.ContentTypeBdBomV6))
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("2023.10.0", "2023.10"))
+	assert.Equal(t, -1, compareVersions("2019.4.0", "2019.12.0"))
+	assert.Equal(t, 1, compareVersions("2018.12.0", "5.0.2"))
+	assert.Equal(t, 1, compareVersions("2023.1.0-SNAPSHOT", "2022.10.1"))
+	assert.Equal(t, -1, compareVersions("", "4.0.0"))
+}
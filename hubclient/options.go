@@ -0,0 +1,240 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const defaultTimeout = time.Minute
+
+// Option configures a Client created by New
+type Option func(*clientOptions) error
+
+type clientOptions struct {
+	httpClient         *http.Client
+	timeout            time.Duration
+	transport          http.RoundTripper
+	tlsOptions         *TLSOptions
+	debugFlags         HubClientDebug
+	userAgent          string
+	credentials        CredentialProvider
+	retryPolicy        *RetryPolicy
+	sessionRelogin     bool
+	tokenRefreshMargin time.Duration
+	middlewares        []Middleware
+	rateLimits         []RateLimit
+	cache              Cache
+	cachePolicies      []CachePolicy
+	tracerProvider     trace.TracerProvider
+	metrics            Metrics
+	logger             Logger
+	cassette           *Cassette
+	maxResponseSize    int64
+	compression        []RequestCompression
+	dryRun             *DryRun
+
+	mediaTypeNegotiation bool
+}
+
+// WithCredentials sets how the Client authenticates. Without credentials the Client is not authenticated.
+func WithCredentials(credentials CredentialProvider) Option {
+	return func(o *clientOptions) error {
+		o.credentials = credentials
+		return nil
+	}
+}
+
+// WithHTTPClient makes the Client send its requests with the given http.Client.
+// It cannot be combined with WithTLS.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *clientOptions) error {
+		o.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of every request, one minute by default
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) error {
+		o.timeout = timeout
+		return nil
+	}
+}
+
+// WithTransport sets the http.RoundTripper used to send requests. It cannot be combined with WithTLS.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *clientOptions) error {
+		o.transport = transport
+		return nil
+	}
+}
+
+// WithTLS sets how the server is verified and how the Client authenticates itself with TLS
+func WithTLS(tlsOptions *TLSOptions) Option {
+	return func(o *clientOptions) error {
+		o.tlsOptions = tlsOptions
+		return nil
+	}
+}
+
+// WithDebugFlags sets the debug output of the Client
+func WithDebugFlags(debugFlags HubClientDebug) Option {
+	return func(o *clientOptions) error {
+		o.debugFlags = debugFlags
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(o *clientOptions) error {
+		o.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests, see SetRetryPolicy
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(o *clientOptions) error {
+		o.retryPolicy = policy
+		return nil
+	}
+}
+
+// WithSessionRelogin makes a Client using UsernamePasswordCredentials log in again when its session expires,
+// see SetSessionRelogin
+func WithSessionRelogin() Option {
+	return func(o *clientOptions) error {
+		o.sessionRelogin = true
+		return nil
+	}
+}
+
+// WithTokenRefreshMargin sets how long before its expiry a bearer token obtained with an API token is refreshed
+func WithTokenRefreshMargin(margin time.Duration) Option {
+	return func(o *clientOptions) error {
+		o.tokenRefreshMargin = margin
+		return nil
+	}
+}
+
+// New creates a Client for the Black Duck server at baseURL, configured with the given options,
+// and authenticates it with the credentials set by WithCredentials
+func New(baseURL string, opts ...Option) (*Client, error) {
+	return NewContext(context.Background(), baseURL, opts...)
+}
+
+// NewContext is New with the authentication requests bound to ctx
+func NewContext(ctx context.Context, baseURL string, opts ...Option) (*Client, error) {
+	o := &clientOptions{
+		tokenRefreshMargin: defaultTokenRefreshMargin,
+	}
+
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+
+	httpClient, err := o.buildHttpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		httpClient:         httpClient,
+		baseURL:            baseURL,
+		debugFlags:         o.debugFlags,
+		userAgent:          o.userAgent,
+		retryPolicy:        o.retryPolicy,
+		sessionRelogin:     o.sessionRelogin,
+		tokenRefreshMargin: o.tokenRefreshMargin,
+		middlewares:        o.middlewares,
+		metrics:            o.metrics,
+		logger:             o.logger,
+		maxResponseSize:    o.maxResponseSize,
+		compression:        o.compression,
+		dryRun:             o.dryRun,
+	}
+
+	if o.tlsOptions != nil && o.tlsOptions.InsecureSkipVerify {
+		c.log().Warn("TLS certificate verification of the Black Duck server is disabled", urlField(baseURL))
+	}
+	c.SetRateLimits(o.rateLimits...)
+	c.SetMediaTypeNegotiation(o.mediaTypeNegotiation)
+	c.SetTracerProvider(o.tracerProvider)
+	if o.cache != nil {
+		c.SetCache(o.cache, o.cachePolicies...)
+	}
+
+	if o.credentials != nil {
+		if err := o.credentials.Authenticate(ctx, c); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func (o *clientOptions) buildHttpClient() (*http.Client, error) {
+	if o.tlsOptions != nil && (o.httpClient != nil || o.transport != nil) {
+		return nil, HubClientErrorf("TLS options cannot be combined with a custom http client or transport")
+	}
+
+	var httpClient *http.Client
+	if o.httpClient != nil {
+		// the caller's client may be shared, http.DefaultClient even, so it is left as it is
+		copied := *o.httpClient
+		httpClient = &copied
+		if o.timeout != 0 {
+			httpClient.Timeout = o.timeout
+		}
+	} else {
+		timeout := o.timeout
+		if timeout == 0 {
+			timeout = defaultTimeout
+		}
+
+		var err error
+		if httpClient, err = NewHTTPClient(timeout, o.tlsOptions); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.transport != nil {
+		httpClient.Transport = o.transport
+	}
+
+	if o.cassette != nil {
+		httpClient.Transport = o.cassette.Wrap(httpClient.Transport)
+	}
+
+	if httpClient.Jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, AnnotateHubClientError(err, "unable to instantiate cookie jar")
+		}
+
+		httpClient.Jar = jar
+	}
+
+	return httpClient, nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_Defaults(t *testing.T) {
+	client, err := New("https://blackduck.example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://blackduck.example.com", client.BaseURL())
+	assert.Equal(t, defaultTimeout, client.httpClient.Timeout)
+	assert.NotNil(t, client.httpClient.Jar)
+	assert.Equal(t, int64(-1), client.GetAuthTokenExpiryTime())
+
+	transport := client.httpClient.Transport.(*http.Transport)
+	assert.False(t, transport.TLSClientConfig.InsecureSkipVerify)
+}
+
+func TestNew_Options(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get(HeaderNameUserAgent)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	client, err := New(server.URL,
+		WithTransport(server.Client().Transport),
+		WithTimeout(5*time.Second),
+		WithUserAgent("hub-client-go-test"),
+		WithDebugFlags(HubClientDebugTimings),
+		WithRetryPolicy(policy),
+		WithSessionRelogin(),
+		WithTokenRefreshMargin(time.Second))
+	require.NoError(t, err)
+
+	assert.Equal(t, 5*time.Second, client.httpClient.Timeout)
+	assert.Equal(t, HubClientDebugTimings, client.debugFlags)
+	assert.True(t, policy == client.retryPolicy)
+	assert.True(t, client.sessionRelogin)
+	assert.Equal(t, time.Second, client.tokenRefreshMargin)
+
+	_, err = client.CurrentVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "hub-client-go-test", userAgent)
+}
+
+func TestNew_RejectsTLSWithCustomTransport(t *testing.T) {
+	_, err := New("https://blackduck.example.com", WithTLS(&TLSOptions{}), WithTransport(http.DefaultTransport))
+	assert.Error(t, err)
+
+	_, err = New("https://blackduck.example.com", WithTLS(&TLSOptions{}), WithHTTPClient(&http.Client{}))
+	assert.Error(t, err)
+}
+
+func TestNew_Credentials(t *testing.T) {
+	ts := &tokenServer{expiresInMs: time.Hour.Milliseconds()}
+	tokenSrv := httptest.NewServer(ts)
+	defer tokenSrv.Close()
+
+	client, err := New(tokenSrv.URL, WithCredentials(ApiTokenCredentials{Token: "api-token"}))
+	require.NoError(t, err)
+	assert.Equal(t, "bearer-1", client.authToken)
+	assert.Equal(t, "csrf-1", client.csrfToken)
+	assert.True(t, client.GetAuthTokenExpiryTime() > time.Now().Unix())
+
+	client, err = New(tokenSrv.URL, WithCredentials(BearerTokenCredentials{Token: "bearer-1"}))
+	require.NoError(t, err)
+	assert.NoError(t, client.HttpGetJSON(tokenSrv.URL, &hubapi.CurrentVersion{}, http.StatusOK))
+
+	ss := &sessionServer{password: "blackduck"}
+	sessionSrv := httptest.NewServer(ss)
+	defer sessionSrv.Close()
+
+	client, err = New(sessionSrv.URL, WithCredentials(UsernamePasswordCredentials{Username: "sysadmin", Password: "blackduck"}), WithSessionRelogin())
+	require.NoError(t, err)
+	assert.Equal(t, "csrf-session-1", client.csrfToken)
+	assert.Equal(t, "sysadmin", client.username)
+
+	_, err = New(sessionSrv.URL, WithCredentials(UsernamePasswordCredentials{Username: "sysadmin", Password: "wrong"}))
+	assert.Error(t, err)
+}
+
+func TestEnvironmentCredentials(t *testing.T) {
+	t.Setenv(EnvApiToken, "")
+	t.Setenv(EnvUsername, "")
+	t.Setenv(EnvPassword, "")
+	t.Setenv(EnvBearerToken, "")
+
+	_, err := EnvironmentCredentials{}.provider()
+	assert.Error(t, err)
+
+	t.Setenv(EnvBearerToken, "bearer")
+	provider, err := EnvironmentCredentials{}.provider()
+	require.NoError(t, err)
+	assert.Equal(t, BearerTokenCredentials{Token: "bearer"}, provider)
+
+	t.Setenv(EnvUsername, "sysadmin")
+	t.Setenv(EnvPassword, "blackduck")
+	provider, err = EnvironmentCredentials{}.provider()
+	require.NoError(t, err)
+	assert.Equal(t, UsernamePasswordCredentials{Username: "sysadmin", Password: "blackduck"}, provider)
+
+	t.Setenv(EnvApiToken, "api-token")
+	provider, err = EnvironmentCredentials{}.provider()
+	require.NoError(t, err)
+	assert.Equal(t, ApiTokenCredentials{Token: "api-token"}, provider)
+}
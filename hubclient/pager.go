@@ -0,0 +1,320 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"context"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+)
+
+// Pager iterates over the items of a paged list, fetching a page only once the items of the
+// previous one are used up:
+//
+//	pager := client.ProjectsPager(nil)
+//	defer pager.Close()
+//	for pager.Next() {
+//		project := pager.Item()
+//		...
+//	}
+//	if err := pager.Err(); err != nil {
+//		...
+//	}
+//
+// A Pager must not be used by several goroutines at once.
+type Pager[T any] struct {
+	ctx     context.Context
+	link    string
+	options *hubapi.GetListOptions
+	fetch   func(ctx context.Context, options *hubapi.GetListOptions) ([]T, int, error)
+
+	page    []T
+	index   int
+	total   int
+	fetched bool
+	item    T
+	err     error
+	closed  bool
+
+	workers     int
+	pending     []chan pageResult[T]
+	nextOffset  int
+	prefetchCtx context.Context
+	cancel      context.CancelFunc
+}
+
+type pageResult[T any] struct {
+	items []T
+	total int
+	err   error
+}
+
+// NewPager creates a Pager over the items of the list at link, which are taken from every page of type L by items.
+// Paging starts at the offset of options and fetches pages of its limit, 100 items if not set.
+// The options are not modified.
+func NewPager[L any, T any](ctx context.Context, c *Client, link string, options *hubapi.GetListOptions, items func(*L) []T) *Pager[T] {
+	return &Pager[T]{
+		ctx:     ctx,
+		link:    link,
+		options: copyListOptions(options),
+		fetch: func(ctx context.Context, options *hubapi.GetListOptions) ([]T, int, error) {
+			var list L
+			if err := c.GetPageContext(ctx, link, options, &list); err != nil {
+				return nil, 0, err
+			}
+
+			total := 0
+			if t, ok := interface{}(&list).(hubapi.TotalCountable); ok {
+				total = t.Total()
+			}
+
+			return items(&list), total, nil
+		},
+	}
+}
+
+// Prefetch makes the Pager fetch the pages following the first one in parallel, with up to workers requests
+// at once. The items are still returned in order. Should the total count of the list change while it is
+// being read, the paging stops with an error, because items may have been skipped or repeated.
+// Prefetch must be called before the first call to Next.
+func (p *Pager[T]) Prefetch(workers int) *Pager[T] {
+	p.workers = workers
+	return p
+}
+
+// Next advances to the next item, fetching the next page if necessary. It returns false once all items
+// have been read, the Pager was closed or fetching a page failed, see Err.
+func (p *Pager[T]) Next() bool {
+	if p.closed || p.err != nil {
+		return false
+	}
+
+	if p.index >= len(p.page) && !p.fetchNext() {
+		return false
+	}
+
+	p.item = p.page[p.index]
+	p.index++
+
+	return true
+}
+
+// Item returns the current item, the zero value before the first call to Next
+func (p *Pager[T]) Item() T {
+	return p.item
+}
+
+// Err returns the error which stopped the paging, if any
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// Total returns the total number of items in the list as reported by the last page fetched
+func (p *Pager[T]) Total() int {
+	return p.total
+}
+
+// Close stops the paging, the remaining pages are not fetched
+func (p *Pager[T]) Close() error {
+	p.closed = true
+	p.page = nil
+	p.stopPrefetching()
+
+	var zero T
+	p.item = zero
+
+	return nil
+}
+
+func (p *Pager[T]) fetchNext() bool {
+	if p.fetched && p.workers > 0 {
+		return p.nextPrefetched()
+	}
+
+	if p.fetched && *p.options.Offset >= p.total {
+		return false
+	}
+
+	if err := p.ctx.Err(); err != nil {
+		p.err = AnnotateHubClientErrorf(err, "paging of %s was interrupted", p.link)
+		return false
+	}
+
+	items, total, err := p.fetch(p.ctx, p.options)
+	if err != nil {
+		p.err = err
+		return false
+	}
+
+	p.fetched = true
+	p.total = total
+	p.page = items
+	p.index = 0
+	p.options.NextPage()
+
+	if p.workers > 0 {
+		p.startPrefetching()
+	}
+
+	// an empty page ends the paging even if the total count promises more
+	return len(items) > 0
+}
+
+func (p *Pager[T]) startPrefetching() {
+	p.prefetchCtx, p.cancel = context.WithCancel(p.ctx)
+	p.nextOffset = *p.options.Offset
+
+	p.prefetch()
+}
+
+// stopPrefetching cancels the pending requests, which are left to finish in the background
+func (p *Pager[T]) stopPrefetching() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.pending = nil
+}
+
+// prefetch starts fetching pages until workers pages are pending or the end of the list is reached
+func (p *Pager[T]) prefetch() {
+	for len(p.pending) < p.workers && p.nextOffset < p.total {
+		options := copyListOptions(p.options)
+		*options.Offset = p.nextOffset
+		p.nextOffset += *options.Limit
+
+		result := make(chan pageResult[T], 1)
+		p.pending = append(p.pending, result)
+
+		ctx, fetch := p.prefetchCtx, p.fetch
+		go func() {
+			items, total, err := fetch(ctx, options)
+			result <- pageResult[T]{items: items, total: total, err: err}
+		}()
+	}
+}
+
+// nextPrefetched waits for the earliest pending page and starts fetching another one in its place
+func (p *Pager[T]) nextPrefetched() bool {
+	if len(p.pending) == 0 {
+		return false
+	}
+
+	next := p.pending[0]
+	p.pending = p.pending[1:]
+
+	var result pageResult[T]
+	select {
+	case result = <-next:
+	case <-p.ctx.Done():
+		result.err = AnnotateHubClientErrorf(p.ctx.Err(), "paging of %s was interrupted", p.link)
+	}
+
+	if result.err == nil && result.total != p.total {
+		result.err = HubClientErrorf("total count of %s changed from %d to %d while paging", p.link, p.total, result.total)
+	}
+
+	if result.err != nil {
+		p.err = result.err
+		p.stopPrefetching()
+		return false
+	}
+
+	p.page = result.items
+	p.index = 0
+	p.prefetch()
+
+	return len(result.items) > 0
+}
+
+// copyListOptions returns a copy of the options with limits set, which can be advanced without affecting the original
+func copyListOptions(options *hubapi.GetListOptions) *hubapi.GetListOptions {
+	options = hubapi.EnsureLimits(options)
+
+	limit, offset := *options.Limit, *options.Offset
+	copied := *options
+	copied.Limit = &limit
+	copied.Offset = &offset
+
+	return &copied
+}
+
+// ProjectsPager returns a Pager over all projects
+func (c *Client) ProjectsPager(options *hubapi.GetListOptions) *Pager[hubapi.Project] {
+	return c.ProjectsPagerContext(context.Background(), options)
+}
+
+func (c *Client) ProjectsPagerContext(ctx context.Context, options *hubapi.GetListOptions) *Pager[hubapi.Project] {
+	projectsURL := hubapi.BuildUrl(c.baseURL, hubapi.ProjectsApi)
+	return NewPager(ctx, c, projectsURL, options, func(l *hubapi.ProjectList) []hubapi.Project { return l.Items })
+}
+
+// ProjectVersionsPager returns a Pager over the versions at link, the versions link of a project
+func (c *Client) ProjectVersionsPager(link hubapi.ResourceLink, options *hubapi.GetListOptions) *Pager[hubapi.ProjectVersion] {
+	return c.ProjectVersionsPagerContext(context.Background(), link, options)
+}
+
+func (c *Client) ProjectVersionsPagerContext(ctx context.Context, link hubapi.ResourceLink, options *hubapi.GetListOptions) *Pager[hubapi.ProjectVersion] {
+	return NewPager(ctx, c, link.Href, options, func(l *hubapi.ProjectVersionList) []hubapi.ProjectVersion { return l.Items })
+}
+
+// ProjectVersionComponentsPager returns a Pager over the BOM components at link, the components link of a project version
+func (c *Client) ProjectVersionComponentsPager(link hubapi.ResourceLink, options *hubapi.GetListOptions) *Pager[hubapi.BomComponent] {
+	return c.ProjectVersionComponentsPagerContext(context.Background(), link, options)
+}
+
+func (c *Client) ProjectVersionComponentsPagerContext(ctx context.Context, link hubapi.ResourceLink, options *hubapi.GetListOptions) *Pager[hubapi.BomComponent] {
+	return NewPager(ctx, c, link.Href, options, func(l *hubapi.BomComponentList) []hubapi.BomComponent { return l.Items })
+}
+
+// CodeLocationsPager returns a Pager over all code locations
+func (c *Client) CodeLocationsPager(options *hubapi.GetListOptions) *Pager[hubapi.CodeLocation] {
+	return c.CodeLocationsPagerContext(context.Background(), options)
+}
+
+func (c *Client) CodeLocationsPagerContext(ctx context.Context, options *hubapi.GetListOptions) *Pager[hubapi.CodeLocation] {
+	codeLocationsURL := hubapi.BuildUrl(c.baseURL, hubapi.CodeLocationsApi)
+	return NewPager(ctx, c, codeLocationsURL, options, func(l *hubapi.CodeLocationList) []hubapi.CodeLocation { return l.Items })
+}
+
+// UsersPager returns a Pager over all users
+func (c *Client) UsersPager(options *hubapi.GetListOptions) *Pager[hubapi.User] {
+	return c.UsersPagerContext(context.Background(), options)
+}
+
+func (c *Client) UsersPagerContext(ctx context.Context, options *hubapi.GetListOptions) *Pager[hubapi.User] {
+	usersURL := hubapi.BuildUrl(c.baseURL, hubapi.UsersApi)
+	return NewPager(ctx, c, usersURL, options, func(l *hubapi.UserList) []hubapi.User { return l.Items })
+}
+
+// PolicyRulesPager returns a Pager over all policy rules. Unlike ListPolicyRules it yields the rules as they
+// are listed, without fetching each of them in full.
+func (c *Client) PolicyRulesPager(options *hubapi.GetListOptions) *Pager[hubapi.PolicyRule] {
+	return c.PolicyRulesPagerContext(context.Background(), options)
+}
+
+func (c *Client) PolicyRulesPagerContext(ctx context.Context, options *hubapi.GetListOptions) *Pager[hubapi.PolicyRule] {
+	policyRuleURL := hubapi.BuildUrl(c.baseURL, hubapi.PolicyRulesApi)
+	return NewPager(ctx, c, policyRuleURL, options, func(l *hubapi.PolicyRuleList) []hubapi.PolicyRule { return l.Items })
+}
+
+// ComponentsPager returns a Pager over the components matching the query of options
+func (c *Client) ComponentsPager(options *hubapi.GetListOptions) *Pager[hubapi.ComponentVariant] {
+	return c.ComponentsPagerContext(context.Background(), options)
+}
+
+func (c *Client) ComponentsPagerContext(ctx context.Context, options *hubapi.GetListOptions) *Pager[hubapi.ComponentVariant] {
+	componentURL := hubapi.BuildUrl(c.baseURL, hubapi.ComponentsApi)
+	return NewPager(ctx, c, componentURL, options, func(l *hubapi.ComponentList) []hubapi.ComponentVariant { return l.Items })
+}
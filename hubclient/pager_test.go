@@ -0,0 +1,230 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// projectsServer serves a list of projects named project-0, project-1, ... page by page
+type projectsServer struct {
+	lock    sync.Mutex
+	total   int
+	offsets []int
+}
+
+func (s *projectsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	total := s.total
+	s.offsets = append(s.offsets, queryInt(r, "offset", 0))
+	s.lock.Unlock()
+
+	offset, limit := queryInt(r, "offset", 0), queryInt(r, "limit", 100)
+
+	list := hubapi.ProjectList{}
+	list.TotalCount = total
+	list.Items = []hubapi.Project{}
+	for i := offset; i < offset+limit && i < total; i++ {
+		list.Items = append(list.Items, hubapi.Project{Name: fmt.Sprintf("project-%d", i)})
+	}
+
+	json.NewEncoder(w).Encode(list)
+}
+
+func (s *projectsServer) requestedOffsets() []int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]int(nil), s.offsets...)
+}
+
+func queryInt(r *http.Request, name string, def int) int {
+	if v, err := strconv.Atoi(r.URL.Query().Get(name)); err == nil {
+		return v
+	}
+	return def
+}
+
+func newProjectsServerClient(t *testing.T, ps *projectsServer) *Client {
+	server := httptest.NewServer(ps)
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+
+	return client
+}
+
+func pageOptions(limit, offset int) *hubapi.GetListOptions {
+	return &hubapi.GetListOptions{Limit: &limit, Offset: &offset}
+}
+
+func TestPagerReadsAllPages(t *testing.T) {
+	ps := &projectsServer{total: 7}
+	client := newProjectsServerClient(t, ps)
+
+	options := pageOptions(3, 0)
+	pager := client.ProjectsPager(options)
+	defer pager.Close()
+
+	var names []string
+	for pager.Next() {
+		names = append(names, pager.Item().Name)
+	}
+
+	require.NoError(t, pager.Err())
+	assert.Len(t, names, 7)
+	assert.Equal(t, "project-0", names[0])
+	assert.Equal(t, "project-6", names[6])
+	assert.Equal(t, 7, pager.Total())
+	assert.Equal(t, []int{0, 3, 6}, ps.requestedOffsets())
+	assert.Equal(t, 0, *options.Offset, "the options of the caller were modified")
+}
+
+func TestPagerIsLazy(t *testing.T) {
+	ps := &projectsServer{total: 10}
+	client := newProjectsServerClient(t, ps)
+
+	pager := client.ProjectsPager(pageOptions(2, 4))
+	assert.Empty(t, ps.requestedOffsets())
+
+	require.True(t, pager.Next())
+	assert.Equal(t, "project-4", pager.Item().Name)
+	require.True(t, pager.Next())
+	require.True(t, pager.Next())
+	assert.Equal(t, "project-6", pager.Item().Name)
+	require.NoError(t, pager.Close())
+
+	assert.False(t, pager.Next())
+	assert.NoError(t, pager.Err())
+	assert.Equal(t, []int{4, 6}, ps.requestedOffsets())
+}
+
+func TestPagerEmptyList(t *testing.T) {
+	ps := &projectsServer{}
+	client := newProjectsServerClient(t, ps)
+
+	pager := client.ProjectsPager(nil)
+	assert.False(t, pager.Next())
+	assert.NoError(t, pager.Err())
+	assert.Equal(t, []int{0}, ps.requestedOffsets())
+}
+
+func TestPagerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+
+	pager := client.UsersPager(nil)
+	assert.False(t, pager.Next())
+	require.Error(t, pager.Err())
+	assert.Equal(t, http.StatusForbidden, pager.Err().(*HubClientError).StatusCode)
+}
+
+func TestPagerContext(t *testing.T) {
+	ps := &projectsServer{total: 4}
+	client := newProjectsServerClient(t, ps)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pager := client.ProjectsPagerContext(ctx, pageOptions(2, 0))
+
+	require.True(t, pager.Next())
+	require.True(t, pager.Next())
+	cancel()
+
+	assert.False(t, pager.Next())
+	require.Error(t, pager.Err())
+	assert.Contains(t, pager.Err().Error(), context.Canceled.Error())
+	assert.Equal(t, []int{0}, ps.requestedOffsets())
+}
+
+func TestForEachPageRejectsNonPointer(t *testing.T) {
+	client, err := New("https://localhost")
+	require.NoError(t, err)
+
+	err = client.ForEachPage("https://localhost/api/projects", nil, hubapi.ProjectList{}, func() error { return nil })
+	assert.Error(t, err)
+}
+
+func TestPagerPrefetchKeepsOrder(t *testing.T) {
+	ps := &projectsServer{total: 25}
+	client := newProjectsServerClient(t, ps)
+
+	pager := client.ProjectsPager(pageOptions(3, 0)).Prefetch(4)
+	defer pager.Close()
+
+	var names []string
+	for pager.Next() {
+		names = append(names, pager.Item().Name)
+	}
+
+	require.NoError(t, pager.Err())
+	require.Len(t, names, 25)
+	for i, name := range names {
+		assert.Equal(t, fmt.Sprintf("project-%d", i), name)
+	}
+
+	offsets := ps.requestedOffsets()
+	assert.Len(t, offsets, 9)
+	assert.Equal(t, 0, offsets[0], "the first page is fetched before the others")
+}
+
+func TestPagerPrefetchDetectsChangedTotal(t *testing.T) {
+	ps := &projectsServer{total: 20}
+	client := newProjectsServerClient(t, ps)
+
+	pager := client.ProjectsPager(pageOptions(5, 0)).Prefetch(1)
+	defer pager.Close()
+
+	// with a single worker the third page is requested once the second one is taken
+	for i := 0; i < 5; i++ {
+		require.True(t, pager.Next())
+	}
+	ps.lock.Lock()
+	ps.total = 21
+	ps.lock.Unlock()
+
+	for pager.Next() {
+	}
+
+	require.Error(t, pager.Err())
+	assert.Contains(t, pager.Err().Error(), "changed from 20 to 21")
+}
+
+func TestPagerPrefetchStopsOnClose(t *testing.T) {
+	ps := &projectsServer{total: 100}
+	client := newProjectsServerClient(t, ps)
+
+	pager := client.ProjectsPager(pageOptions(10, 0)).Prefetch(2)
+	require.True(t, pager.Next())
+	require.NoError(t, pager.Close())
+	assert.False(t, pager.Next())
+
+	assert.True(t, len(ps.requestedOffsets()) <= 3)
+}
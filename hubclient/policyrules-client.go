@@ -15,15 +15,19 @@
 package hubclient
 
 import (
+	"context"
 	"github.com/blackducksoftware/hub-client-go/hubapi"
-	log "github.com/sirupsen/logrus"
 )
 
 func (c *Client) ListPolicyRules(options *hubapi.GetListOptions) (*hubapi.PolicyRuleList, error) {
+	return c.ListPolicyRulesContext(context.Background(), options)
+}
+
+func (c *Client) ListPolicyRulesContext(ctx context.Context, options *hubapi.GetListOptions) (*hubapi.PolicyRuleList, error) {
 	policyRuleURL := hubapi.BuildUrl(c.baseURL, hubapi.PolicyRulesApi)
 
 	var policyRuleList hubapi.PolicyRuleList
-	err := c.GetPage(policyRuleURL, options, &policyRuleList)
+	err := c.GetPageContext(ctx, policyRuleURL, options, &policyRuleList)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve policy rule list")
@@ -34,7 +38,7 @@ func (c *Client) ListPolicyRules(options *hubapi.GetListOptions) (*hubapi.Policy
 		link := hubapi.ResourceLink{
 			Href: pr.Meta.Href,
 		}
-		rule, err := c.GetPolicyRule(link)
+		rule, err := c.GetPolicyRuleContext(ctx, link)
 		if err != nil {
 			return nil, err
 		}
@@ -46,8 +50,12 @@ func (c *Client) ListPolicyRules(options *hubapi.GetListOptions) (*hubapi.Policy
 }
 
 func (c *Client) GetPolicyRule(link hubapi.ResourceLink) (*hubapi.PolicyRule, error) {
+	return c.GetPolicyRuleContext(context.Background(), link)
+}
+
+func (c *Client) GetPolicyRuleContext(ctx context.Context, link hubapi.ResourceLink) (*hubapi.PolicyRule, error) {
 	var policyRule hubapi.PolicyRule
-	err := c.HttpGetJSON(link.Href, &policyRule, 200)
+	err := c.HttpGetJSONContext(ctx, link.Href, &policyRule, 200)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve a policy rule")
@@ -57,21 +65,29 @@ func (c *Client) GetPolicyRule(link hubapi.ResourceLink) (*hubapi.PolicyRule, er
 }
 
 func (c *Client) CreatePolicyRule(policyRuleRequest *hubapi.PolicyRuleRequest) (string, error) {
+	return c.CreatePolicyRuleContext(context.Background(), policyRuleRequest)
+}
+
+func (c *Client) CreatePolicyRuleContext(ctx context.Context, policyRuleRequest *hubapi.PolicyRuleRequest) (string, error) {
 	policyRuleURL := hubapi.BuildUrl(c.baseURL, hubapi.PolicyRulesApi)
 
-	location, err := c.HttpPostJSON(policyRuleURL, policyRuleRequest, "application/json", 201)
+	location, err := c.HttpPostJSONContext(ctx, policyRuleURL, policyRuleRequest, "application/json", 201)
 
 	if err != nil {
 		return location, TraceHubClientError(err)
 	}
 
 	if location == "" {
-		log.Warnf("Did not get a location header back for policy rule creation")
+		c.log().Warn("Did not get a location header back for policy rule creation")
 	}
 
 	return location, err
 }
 
 func (c *Client) DeletePolicyRule(policyRuleURL string) error {
-	return c.HttpDelete(policyRuleURL, "application/json", 204)
+	return c.DeletePolicyRuleContext(context.Background(), policyRuleURL)
+}
+
+func (c *Client) DeletePolicyRuleContext(ctx context.Context, policyRuleURL string) error {
+	return c.HttpDeleteContext(ctx, policyRuleURL, "application/json", 204)
 }
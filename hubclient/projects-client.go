@@ -15,8 +15,8 @@
 package hubclient
 
 import (
+	"context"
 	"github.com/blackducksoftware/hub-client-go/hubapi"
-	log "github.com/sirupsen/logrus"
 )
 
 // What about continuation for these?
@@ -25,10 +25,14 @@ import (
 // Or maybe a special return type that can keep querying for all of them when it runs out?
 // Is there any iterator type in GoLang?
 func (c *Client) ListProjects(options *hubapi.GetListOptions) (*hubapi.ProjectList, error) {
+	return c.ListProjectsContext(context.Background(), options)
+}
+
+func (c *Client) ListProjectsContext(ctx context.Context, options *hubapi.GetListOptions) (*hubapi.ProjectList, error) {
 	projectsURL := hubapi.BuildUrl(c.baseURL, hubapi.ProjectsApi)
 
 	var projectList hubapi.ProjectList
-	err := c.GetPage(projectsURL, options, &projectList)
+	err := c.GetPageContext(ctx, projectsURL, options, &projectList)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve project list")
@@ -38,9 +42,13 @@ func (c *Client) ListProjects(options *hubapi.GetListOptions) (*hubapi.ProjectLi
 }
 
 func (c *Client) GetProject(link hubapi.ResourceLink) (*hubapi.Project, error) {
+	return c.GetProjectContext(context.Background(), link)
+}
+
+func (c *Client) GetProjectContext(ctx context.Context, link hubapi.ResourceLink) (*hubapi.Project, error) {
 
 	var project hubapi.Project
-	err := c.HttpGetJSON(link.Href, &project, 200)
+	err := c.HttpGetJSONContext(ctx, link.Href, &project, 200)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve a project")
@@ -50,34 +58,50 @@ func (c *Client) GetProject(link hubapi.ResourceLink) (*hubapi.Project, error) {
 }
 
 func (c *Client) CreateProject(projectRequest *hubapi.ProjectRequest) (string, error) {
+	return c.CreateProjectContext(context.Background(), projectRequest)
+}
+
+func (c *Client) CreateProjectContext(ctx context.Context, projectRequest *hubapi.ProjectRequest) (string, error) {
 	projectsURL := hubapi.BuildUrl(c.baseURL, hubapi.ProjectsApi)
-	location, err := c.HttpPostJSON(projectsURL, projectRequest, "application/json", 201)
+	location, err := c.HttpPostJSONContext(ctx, projectsURL, projectRequest, "application/json", 201)
 
 	if err != nil {
 		return location, TraceHubClientError(err)
 	}
 
 	if location == "" {
-		log.Warnf("Did not get a location header back for project creation")
+		c.log().Warn("Did not get a location header back for project creation")
 	}
 
 	return location, err
 }
 
 func (c *Client) DeleteProject(projectURL string) error {
-	return c.HttpDelete(projectURL, "application/json", 204)
+	return c.DeleteProjectContext(context.Background(), projectURL)
+}
+
+func (c *Client) DeleteProjectContext(ctx context.Context, projectURL string) error {
+	return c.HttpDeleteContext(ctx, projectURL, "application/json", 204)
 }
 
 // DeleteProjectVersion deletes a project version using
 // https://<base_hub_URL>/api.html#!/project45version45rest45server/deleteVersionUsingDELETE
 func (c *Client) DeleteProjectVersion(projectVersionURL string) error {
-	return c.HttpDelete(projectVersionURL, "application/json", 204)
+	return c.DeleteProjectVersionContext(context.Background(), projectVersionURL)
+}
+
+func (c *Client) DeleteProjectVersionContext(ctx context.Context, projectVersionURL string) error {
+	return c.HttpDeleteContext(ctx, projectVersionURL, "application/json", 204)
 }
 
 func (c *Client) ListProjectVersions(link hubapi.ResourceLink, options *hubapi.GetListOptions) (*hubapi.ProjectVersionList, error) {
+	return c.ListProjectVersionsContext(context.Background(), link, options)
+}
+
+func (c *Client) ListProjectVersionsContext(ctx context.Context, link hubapi.ResourceLink, options *hubapi.GetListOptions) (*hubapi.ProjectVersionList, error) {
 
 	var versionList hubapi.ProjectVersionList
-	err := c.GetPage(link.Href, options, &versionList)
+	err := c.GetPageContext(ctx, link.Href, options, &versionList)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve project version list")
@@ -87,9 +111,13 @@ func (c *Client) ListProjectVersions(link hubapi.ResourceLink, options *hubapi.G
 }
 
 func (c *Client) GetProjectVersion(link hubapi.ResourceLink) (*hubapi.ProjectVersion, error) {
+	return c.GetProjectVersionContext(context.Background(), link)
+}
+
+func (c *Client) GetProjectVersionContext(ctx context.Context, link hubapi.ResourceLink) (*hubapi.ProjectVersion, error) {
 
 	var projectVersion hubapi.ProjectVersion
-	err := c.HttpGetJSON(link.Href, &projectVersion, 200)
+	err := c.HttpGetJSONContext(ctx, link.Href, &projectVersion, 200)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve a project version")
@@ -99,24 +127,32 @@ func (c *Client) GetProjectVersion(link hubapi.ResourceLink) (*hubapi.ProjectVer
 }
 
 func (c *Client) CreateProjectVersion(link hubapi.ResourceLink, projectVersionRequest *hubapi.ProjectVersionRequest) (string, error) {
+	return c.CreateProjectVersionContext(context.Background(), link, projectVersionRequest)
+}
+
+func (c *Client) CreateProjectVersionContext(ctx context.Context, link hubapi.ResourceLink, projectVersionRequest *hubapi.ProjectVersionRequest) (string, error) {
 
-	location, err := c.HttpPostJSON(link.Href, projectVersionRequest, "application/json", 201)
+	location, err := c.HttpPostJSONContext(ctx, link.Href, projectVersionRequest, "application/json", 201)
 
 	if err != nil {
 		return location, TraceHubClientError(err)
 	}
 
 	if location == "" {
-		log.Warnf("Did not get a location header back for project version creation")
+		c.log().Warn("Did not get a location header back for project version creation")
 	}
 
 	return location, err
 }
 
 func (c *Client) GetProjectVersionRiskProfile(link hubapi.ResourceLink) (*hubapi.ProjectVersionRiskProfile, error) {
+	return c.GetProjectVersionRiskProfileContext(context.Background(), link)
+}
+
+func (c *Client) GetProjectVersionRiskProfileContext(ctx context.Context, link hubapi.ResourceLink) (*hubapi.ProjectVersionRiskProfile, error) {
 
 	var riskProfile hubapi.ProjectVersionRiskProfile
-	err := c.HttpGetJSON(link.Href, &riskProfile, 200)
+	err := c.HttpGetJSONContext(ctx, link.Href, &riskProfile, 200)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve a project version risk profile")
@@ -126,9 +162,13 @@ func (c *Client) GetProjectVersionRiskProfile(link hubapi.ResourceLink) (*hubapi
 }
 
 func (c *Client) GetProjectVersionPolicyStatus(link hubapi.ResourceLink) (*hubapi.ProjectVersionPolicyStatus, error) {
+	return c.GetProjectVersionPolicyStatusContext(context.Background(), link)
+}
+
+func (c *Client) GetProjectVersionPolicyStatusContext(ctx context.Context, link hubapi.ResourceLink) (*hubapi.ProjectVersionPolicyStatus, error) {
 
 	var policyStatus hubapi.ProjectVersionPolicyStatus
-	err := c.HttpGetJSON(link.Href, &policyStatus, 200)
+	err := c.HttpGetJSONContext(ctx, link.Href, &policyStatus, 200)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve a project version policy status")
@@ -138,15 +178,19 @@ func (c *Client) GetProjectVersionPolicyStatus(link hubapi.ResourceLink) (*hubap
 }
 
 func (c *Client) AssignUserToProject(link hubapi.ResourceLink, userAssignmentRequest *hubapi.UserAssignmentRequest) (string, error) {
+	return c.AssignUserToProjectContext(context.Background(), link, userAssignmentRequest)
+}
+
+func (c *Client) AssignUserToProjectContext(ctx context.Context, link hubapi.ResourceLink, userAssignmentRequest *hubapi.UserAssignmentRequest) (string, error) {
 
-	location, err := c.HttpPostJSON(link.Href, userAssignmentRequest, "application/json", 201)
+	location, err := c.HttpPostJSONContext(ctx, link.Href, userAssignmentRequest, "application/json", 201)
 
 	if err != nil {
 		return location, TraceHubClientError(err)
 	}
 
 	if location == "" {
-		log.Warnf("Did not get a location header back for project user assignment")
+		c.log().Warn("Did not get a location header back for project user assignment")
 	}
 
 	return location, err
@@ -0,0 +1,116 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package prommetrics reports the traffic of hubclient Clients to Prometheus:
+//
+//	collector := prommetrics.NewCollector("myapp")
+//	prometheus.MustRegister(collector)
+//	client, err := hubclient.New(url, hubclient.WithMetrics(collector), ...)
+package prommetrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubclient"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector is a prometheus.Collector and hubclient.Metrics reporting the traffic of the Clients using it.
+// All metrics are labelled with the HTTP method and the route of the request.
+type Collector struct {
+	requests       *prometheus.CounterVec
+	duration       *prometheus.HistogramVec
+	errors         *prometheus.CounterVec
+	bytesSent      *prometheus.CounterVec
+	bytesReceived  *prometheus.CounterVec
+	pollIterations *prometheus.CounterVec
+	authRefreshes  *prometheus.CounterVec
+}
+
+var _ hubclient.Metrics = (*Collector)(nil)
+
+var labels = []string{"method", "route"}
+
+// NewCollector creates a Collector whose metrics are prefixed with namespace, if not empty
+func NewCollector(namespace string) *Collector {
+	opts := func(name, help string) prometheus.Opts {
+		return prometheus.Opts{Namespace: namespace, Subsystem: "blackduck_client", Name: name, Help: help}
+	}
+
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts(opts("requests_total",
+			"Number of requests sent to the Black Duck server")), labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "blackduck_client",
+			Name:      "request_duration_seconds",
+			Help:      "Time until the response headers of a request were received",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts(opts("errors_total",
+			"Number of requests which failed, by response status code, 0 if no response was received")),
+			append(labels, "status_code")),
+		bytesSent: prometheus.NewCounterVec(prometheus.CounterOpts(opts("sent_bytes_total",
+			"Number of bytes sent in request bodies")), labels),
+		bytesReceived: prometheus.NewCounterVec(prometheus.CounterOpts(opts("received_bytes_total",
+			"Number of bytes received in response bodies")), labels),
+		pollIterations: prometheus.NewCounterVec(prometheus.CounterOpts(opts("rapid_scan_polls_total",
+			"Number of times the results of rapid scans were polled")), labels),
+		authRefreshes: prometheus.NewCounterVec(prometheus.CounterOpts(opts("auth_refreshes_total",
+			"Number of times an expired bearer token or session was renewed")), labels),
+	}
+}
+
+func (m *Collector) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.requests, m.duration, m.errors, m.bytesSent, m.bytesReceived, m.pollIterations, m.authRefreshes}
+}
+
+func (m *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range m.collectors() {
+		c.Describe(ch)
+	}
+}
+
+func (m *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range m.collectors() {
+		c.Collect(ch)
+	}
+}
+
+func (m *Collector) ObserveRequest(method, route string, statusCode int, duration time.Duration, sentBytes int64) {
+	if sentBytes > 0 {
+		m.bytesSent.WithLabelValues(method, route).Add(float64(sentBytes))
+	}
+
+	m.requests.WithLabelValues(method, route).Inc()
+	m.duration.WithLabelValues(method, route).Observe(duration.Seconds())
+
+	if statusCode == 0 || statusCode >= http.StatusBadRequest {
+		m.errors.WithLabelValues(method, route, strconv.Itoa(statusCode)).Inc()
+	}
+}
+
+func (m *Collector) ObserveReceivedBytes(method, route string, n int) {
+	m.bytesReceived.WithLabelValues(method, route).Add(float64(n))
+}
+
+func (m *Collector) ObservePoll(route string) {
+	m.pollIterations.WithLabelValues(http.MethodGet, route).Inc()
+}
+
+func (m *Collector) ObserveAuthRefresh(route string) {
+	m.authRefreshes.WithLabelValues(http.MethodPost, route).Inc()
+}
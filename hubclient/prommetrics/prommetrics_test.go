@@ -0,0 +1,75 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prommetrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/blackducksoftware/hub-client-go/hubclient"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/projects/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"name": "project"}`))
+	}))
+	defer server.Close()
+
+	collector := NewCollector("test")
+	client, err := hubclient.New(server.URL, hubclient.WithHTTPClient(server.Client()), hubclient.WithMetrics(collector))
+	require.NoError(t, err)
+
+	for _, id := range []string{"7d5a4b0e-1b8a-4b4e-9a8e-6f0c3c1d2e11", "0c8c9d73-6f9b-4e67-a3f4-0c2e7c6c1a11"} {
+		_, err := client.GetProject(hubapi.ResourceLink{Href: server.URL + "/api/projects/" + id})
+		require.NoError(t, err)
+	}
+	_, err = client.GetProject(hubapi.ResourceLink{Href: server.URL + "/api/projects/missing"})
+	require.Error(t, err)
+	_, err = client.HttpPostJSONExpectResult(server.URL+"/api/projects", "payload", nil, "application/json", http.StatusOK)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(collector.requests.WithLabelValues("GET", "/api/projects/{id}")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.requests.WithLabelValues("GET", "/api/projects/missing")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.errors.WithLabelValues("GET", "/api/projects/missing", "404")))
+	assert.Equal(t, float64(2*len(`{"name": "project"}`)), testutil.ToFloat64(collector.bytesReceived.WithLabelValues("GET", "/api/projects/{id}")))
+	assert.Equal(t, float64(len(`"payload"`)+1), testutil.ToFloat64(collector.bytesSent.WithLabelValues("POST", "/api/projects")))
+
+	collector.ObservePoll("/api/developer-scans/{id}")
+	collector.ObserveAuthRefresh(hubapi.AuthenticateApi)
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.pollIterations.WithLabelValues("GET", "/api/developer-scans/{id}")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.authRefreshes.WithLabelValues("POST", hubapi.AuthenticateApi)))
+
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(collector))
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	var names []string
+	for _, family := range families {
+		names = append(names, family.GetName())
+	}
+	assert.Contains(t, names, "test_blackduck_client_requests_total")
+	assert.Contains(t, names, "test_blackduck_client_request_duration_seconds")
+}
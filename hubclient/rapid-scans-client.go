@@ -15,15 +15,14 @@
 package hubclient
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/blackducksoftware/hub-client-go/hubapi"
-	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -43,11 +42,15 @@ type ChunkIterator interface {
 }
 
 func (c *Client) StartRapidScan(bdioHeaderContent string) (error, string) {
+	return c.StartRapidScanContext(context.Background(), bdioHeaderContent)
+}
+
+func (c *Client) StartRapidScanContext(ctx context.Context, bdioHeaderContent string) (error, string) {
 	rapidScansURL := hubapi.BuildUrl(c.baseURL, hubapi.DeveloperScansApi)
-	bdioUploadEndpoint, err := c.HttpPostString(rapidScansURL, bdioHeaderContent, hubapi.ContentTypeRapidScanRequest, http.StatusCreated)
+	bdioUploadEndpoint, err := c.HttpPostStringContext(ctx, rapidScansURL, bdioHeaderContent, hubapi.ContentTypeRapidScanRequest, http.StatusCreated)
 
 	if err != nil {
-		log.Error("Error kicking off a rapid scan.", err)
+		c.log().Error("Error kicking off a rapid scan", urlField(rapidScansURL), errorField(err))
 		return err, ""
 	}
 
@@ -55,29 +58,45 @@ func (c *Client) StartRapidScan(bdioHeaderContent string) (error, string) {
 }
 
 func (c *Client) UploadBdioFiles(bdioUploadEndpoint string, bdioContents []string) error {
+	return c.UploadBdioFilesContext(context.Background(), bdioUploadEndpoint, bdioContents)
+}
+
+func (c *Client) UploadBdioFilesContext(ctx context.Context, bdioUploadEndpoint string, bdioContents []string) error {
 	iterator := NewArrayChunkIterator(bdioContents)
-	return c.UploadBdioFilesByChunk(bdioUploadEndpoint, len(bdioContents), &iterator)
+	return c.UploadBdioFilesByChunkContext(ctx, bdioUploadEndpoint, len(bdioContents), &iterator)
 }
 
 func (c *Client) UploadBdioFilesByChunk(bdioUploadEndpoint string, chunkCount int, iterator ChunkIterator) error {
+	return c.UploadBdioFilesByChunkContext(context.Background(), bdioUploadEndpoint, chunkCount, iterator)
+}
+
+func (c *Client) UploadBdioFilesByChunkContext(ctx context.Context, bdioUploadEndpoint string, chunkCount int, iterator ChunkIterator) (err error) {
+	ctx, span := c.startSpan(ctx, "UploadBdioFilesByChunk",
+		attribute.String("url.full", bdioUploadEndpoint), attribute.Int("blackduck.chunk_count", chunkCount))
+	defer func() { endSpan(span, err) }()
+
 	header := http.Header{}
 	header.Add(headerBdMode, bdModeAppend)
 	header.Add(headerBdDocumentCount, strconv.Itoa(chunkCount))
 
 	for iterator.HasNext() {
 		bdioContent, err := iterator.Next()
+		if err != nil {
+			c.log().Error("Error reading bdio chunk", urlField(bdioUploadEndpoint), errorField(err))
+			return AnnotateHubClientError(err, "error reading bdio chunk")
+		}
 
-		err = c.HttpPutStringWithHeader(bdioUploadEndpoint, bdioContent, hubapi.ContentTypeRapidScanRequest, http.StatusAccepted, header)
+		err = c.HttpPutStringWithHeaderContext(ctx, bdioUploadEndpoint, bdioContent, hubapi.ContentTypeRapidScanRequest, http.StatusAccepted, header)
 		if err != nil {
-			log.Error("Error uploading bdio files.", err)
+			c.log().Error("Error uploading bdio files", urlField(bdioUploadEndpoint), errorField(err))
 			return err
 		}
 	}
 
 	header.Set(headerBdMode, bdModeFinish)
-	err := c.HttpPutStringWithHeader(bdioUploadEndpoint, "", hubapi.ContentTypeRapidScanRequest, http.StatusAccepted, header)
+	err = c.HttpPutStringWithHeaderContext(ctx, bdioUploadEndpoint, "", hubapi.ContentTypeRapidScanRequest, http.StatusAccepted, header)
 	if err != nil {
-		log.Error("Error uploading bdio files.", err)
+		c.log().Error("Error uploading bdio files", urlField(bdioUploadEndpoint), errorField(err))
 		return err
 	}
 
@@ -85,6 +104,15 @@ func (c *Client) UploadBdioFilesByChunk(bdioUploadEndpoint string, chunkCount in
 }
 
 func (c *Client) PollRapidScanResults(rapidScanEndpoint string, interval, timeout time.Duration, pageLimit int, option RapidScanOpts) (error, *hubapi.RapidScanResult) {
+	return c.PollRapidScanResultsContext(context.Background(), rapidScanEndpoint, interval, timeout, pageLimit, option)
+}
+
+// PollRapidScanResultsContext polls the rapid scan at the given interval until its results are ready,
+// the timeout elapses or ctx is done, and then reads all pages of the result.
+func (c *Client) PollRapidScanResultsContext(ctx context.Context, rapidScanEndpoint string, interval, timeout time.Duration, pageLimit int, option RapidScanOpts) (err error, result *hubapi.RapidScanResult) {
+	ctx, span := c.startSpan(ctx, "PollRapidScanResults", attribute.String("url.full", rapidScanEndpoint))
+	defer func() { endSpan(span, err) }()
+
 	offset := 0
 	ticker := time.NewTicker(interval)
 	timeoutTimer := time.NewTimer(timeout)
@@ -92,16 +120,21 @@ func (c *Client) PollRapidScanResults(rapidScanEndpoint string, interval, timeou
 
 	for {
 		select {
+		case <-ctx.Done():
+			ticker.Stop()
+			timeoutTimer.Stop()
+			return AnnotateHubClientErrorf(ctx.Err(), "The polling for rapid scan result was interrupted: %s", rapidScanEndpoint), nil
 		case <-timeoutTimer.C:
 			ticker.Stop()
-			return errors.New(fmt.Sprintf("The polling for rapid scan result timed out: %s", rapidScanEndpoint)), nil
+			return AnnotateHubClientErrorf(ErrTimeout, "The polling for rapid scan result timed out: %s", rapidScanEndpoint), nil
 		case <-ticker.C:
-			err, statusCode := c.fetchResults(rapidScanEndpoint, offset, pageLimit, &body, option)
+			c.observePoll(rapidScanEndpoint)
+			err, statusCode := c.fetchResults(ctx, rapidScanEndpoint, offset, pageLimit, &body, option)
 
 			if err != nil {
 				ticker.Stop()
 				timeoutTimer.Stop()
-				log.Error("Error fetching rapid scan result", err)
+				c.log().Error("Error fetching rapid scan result", urlField(rapidScanEndpoint), errorField(err))
 				return err, nil
 			}
 
@@ -109,7 +142,7 @@ func (c *Client) PollRapidScanResults(rapidScanEndpoint string, interval, timeou
 				ticker.Stop()
 				timeoutTimer.Stop()
 
-				err, result := parseBody(body)
+				err, result := c.parseBody(body)
 
 				if err != nil {
 					return err, result
@@ -119,19 +152,19 @@ func (c *Client) PollRapidScanResults(rapidScanEndpoint string, interval, timeou
 				for result.Count > len(result.Components) {
 					//increase offset to fetch the next page of results
 					offset += pageLimit
-					err, statusCode := c.fetchResults(rapidScanEndpoint, offset, pageLimit, &body, option)
+					err, statusCode := c.fetchResults(ctx, rapidScanEndpoint, offset, pageLimit, &body, option)
 
 					if err != nil {
-						log.Error("Error fetching rapid scan result", err)
+						c.log().Error("Error fetching rapid scan result", urlField(rapidScanEndpoint), errorField(err))
 						return err, result
 					}
 
 					if statusCode != http.StatusOK {
-						log.Errorf("Error fetching subsequent pages of a rapid scan result. Code: %d", statusCode)
-						return err, result
+						c.log().Error("Error fetching subsequent pages of a rapid scan result", urlField(rapidScanEndpoint), statusField(statusCode))
+						return HubClientStatusCodeErrorf(statusCode, "got a %d response for page %d of the rapid scan result %s", statusCode, offset/pageLimit+1, rapidScanEndpoint), result
 					}
 
-					err, pagedResult := parseBody(body)
+					err, pagedResult := c.parseBody(body)
 					if err != nil {
 						return err, result
 					}
@@ -146,34 +179,38 @@ func (c *Client) PollRapidScanResults(rapidScanEndpoint string, interval, timeou
 }
 
 func (c *Client) FetchResults(rapidScanEndpoint string, offset int, pageLimit int, option RapidScanOpts) (err error, httpStatus int, result *hubapi.RapidScanResult) {
+	return c.FetchResultsContext(context.Background(), rapidScanEndpoint, offset, pageLimit, option)
+}
+
+func (c *Client) FetchResultsContext(ctx context.Context, rapidScanEndpoint string, offset int, pageLimit int, option RapidScanOpts) (err error, httpStatus int, result *hubapi.RapidScanResult) {
 	var body string
-	err, statusCode := c.fetchResults(rapidScanEndpoint, offset, pageLimit, &body, option)
+	err, statusCode := c.fetchResults(ctx, rapidScanEndpoint, offset, pageLimit, &body, option)
 	if err != nil || statusCode != http.StatusOK {
 		return err, statusCode, nil
 	}
-	err, result = parseBody(body)
+	err, result = c.parseBody(body)
 	return err, statusCode, result
 }
 
-func parseBody(body string) (error, *hubapi.RapidScanResult) {
+func (c *Client) parseBody(body string) (error, *hubapi.RapidScanResult) {
 	var pagedResult *hubapi.RapidScanResult
 	err := json.Unmarshal([]byte(body), &pagedResult)
 
 	if err != nil {
-		log.Error("Error parsing rapid scan result", err)
-		return err, nil
+		c.log().Error("Error parsing rapid scan result", errorField(err))
+		return AnnotateHubClientError(err, "error parsing rapid scan result"), nil
 	}
 
 	return nil, pagedResult
 }
 
-func (c *Client) fetchResults(rapidScanEndpoint string, offset int, limit int, body *string, option RapidScanOpts) (error, int) {
+func (c *Client) fetchResults(ctx context.Context, rapidScanEndpoint string, offset int, limit int, body *string, option RapidScanOpts) (error, int) {
 	url := hubapi.BuildUrl(rapidScanEndpoint, hubapi.FullResultsApi)
 	params := make(map[string]string)
 	params["offset"] = strconv.Itoa(offset)
 	params["limit"] = strconv.Itoa(limit)
 	params["nonVulnerableComponents"] = strconv.FormatBool(option.IncludeNonVulnerableComponents)
 	url = hubapi.AddParameters(url, params)
-	err, statusCode := c.HttpGetString(url, body, []int{http.StatusOK, http.StatusNotFound}, hubapi.ContentTypeRapidScanResults)
+	err, statusCode := c.HttpGetStringContext(ctx, url, body, []int{http.StatusOK, http.StatusNotFound}, hubapi.ContentTypeRapidScanResults)
 	return err, statusCode
 }
@@ -0,0 +1,235 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RateLimit throttles the requests matching its Method and PathPrefix, with a token bucket limiting
+// how often they are sent and a cap on how many of them are in flight at the same time. A request is in
+// flight until its response body has been read to the end or closed.
+// All limits matching a request apply, so a limit for all requests can be combined with stricter ones
+// for single endpoints. Every attempt of a retried request counts separately.
+type RateLimit struct {
+	// Method is the HTTP method of the requests limited, all methods if empty
+	Method string
+	// PathPrefix is the beginning of the URL path of the requests limited, such as /api/projects, all paths
+	// if empty. It is matched on whole path segments: /api/projects matches /api/projects/1 but not
+	// /api/projectsX.
+	PathPrefix string
+	// RequestsPerSecond is the rate at which requests are sent on average, zero means no limit
+	RequestsPerSecond float64
+	// Burst is the number of requests which may be sent at once before the rate applies, at least 1
+	Burst int
+	// MaxInFlight caps the number of requests waiting for a response, zero means no cap
+	MaxInFlight int
+}
+
+// SetRateLimits replaces the rate limits of the Client. Without limits requests are sent right away.
+// SetRateLimits must not be called while requests are in flight.
+func (c *Client) SetRateLimits(limits ...RateLimit) {
+	c.limiters = nil
+	for _, limit := range limits {
+		c.limiters = append(c.limiters, newLimiter(limit))
+	}
+}
+
+// WithRateLimits sets the rate limits of the Client, see SetRateLimits
+func WithRateLimits(limits ...RateLimit) Option {
+	return func(o *clientOptions) error {
+		o.rateLimits = append(o.rateLimits, limits...)
+		return nil
+	}
+}
+
+type limiter struct {
+	limit RateLimit
+
+	lock   sync.Mutex
+	tokens float64
+	last   time.Time
+
+	slots chan struct{}
+}
+
+func newLimiter(limit RateLimit) *limiter {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+
+	l := &limiter{
+		limit:  limit,
+		tokens: float64(limit.Burst),
+		last:   time.Now(),
+	}
+
+	if limit.MaxInFlight > 0 {
+		l.slots = make(chan struct{}, limit.MaxInFlight)
+	}
+
+	return l
+}
+
+func (l *limiter) matches(req *http.Request) bool {
+	if l.limit.Method != "" && !strings.EqualFold(l.limit.Method, req.Method) {
+		return false
+	}
+
+	return hasPathPrefix(req.URL.Path, l.limit.PathPrefix)
+}
+
+// hasPathPrefix reports whether path begins with the path segments of prefix
+func hasPathPrefix(path, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+
+	return len(path) == len(prefix) || strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/'
+}
+
+// reserve takes a token from the bucket and returns how long to wait until it may be used
+func (l *limiter) reserve() time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.limit.RequestsPerSecond
+	if burst := float64(l.limit.Burst); l.tokens > burst {
+		l.tokens = burst
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.limit.RequestsPerSecond * float64(time.Second))
+}
+
+// cancel returns a token which was reserved but not used
+func (l *limiter) cancel() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.tokens++
+}
+
+func (l *limiter) wait(ctx context.Context) error {
+	if l.limit.RequestsPerSecond <= 0 {
+		return nil
+	}
+
+	if err := sleepContext(ctx, l.reserve()); err != nil {
+		l.cancel()
+		return err
+	}
+
+	return nil
+}
+
+func (l *limiter) acquire(ctx context.Context) error {
+	if l.slots == nil {
+		return nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *limiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// roundTrip sends a single attempt of the request once the rate limits matching it allow it
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	var matching []*limiter
+	for _, l := range c.limiters {
+		if l.matches(req) {
+			matching = append(matching, l)
+		}
+	}
+
+	if len(matching) == 0 {
+		return c.transmit(req)
+	}
+
+	ctx := req.Context()
+	for _, l := range matching {
+		if err := l.wait(ctx); err != nil {
+			return nil, errors.WithMessagef(err, "waiting for the rate limit of %s %s", req.Method, req.URL.Path)
+		}
+	}
+
+	// the slots are always taken in the same order, so that requests cannot block each other
+	for i, l := range matching {
+		if err := l.acquire(ctx); err != nil {
+			for _, taken := range matching[:i] {
+				taken.release()
+			}
+			return nil, errors.WithMessagef(err, "waiting for a free slot to send %s %s", req.Method, req.URL.Path)
+		}
+	}
+
+	release := func() {
+		for _, l := range matching {
+			l.release()
+		}
+	}
+
+	resp, err := c.transmit(req)
+	if err != nil || resp == nil || resp.Body == nil || resp.Body == http.NoBody {
+		release()
+		return resp, err
+	}
+
+	// the response is in flight until its body has been read
+	resp.Body = &releasingReadCloser{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingReadCloser releases the slots of a request once its response body is read to the end or closed
+type releasingReadCloser struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (r *releasingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err == io.EOF {
+		r.once.Do(r.release)
+	}
+	return n, err
+}
+
+func (r *releasingReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
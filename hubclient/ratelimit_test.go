@@ -0,0 +1,223 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitThrottlesMatchingRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version": "2024.1.0"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithRateLimits(RateLimit{
+		Method:            http.MethodGet,
+		PathPrefix:        hubapi.CurrentVersionApi,
+		RequestsPerSecond: 20,
+		Burst:             1,
+	}))
+	require.NoError(t, err)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := client.CurrentVersion()
+		require.NoError(t, err)
+	}
+
+	// the first request uses the burst, the other three wait 50ms each
+	assert.True(t, time.Since(start) >= 140*time.Millisecond, "took %s", time.Since(start))
+
+	start = time.Now()
+	var body string
+	err, _ = client.HttpGetString(server.URL+"/api/projects", &body, []int{200})
+	require.NoError(t, err)
+	assert.True(t, time.Since(start) < 40*time.Millisecond, "unmatched request was throttled")
+}
+
+func TestRateLimitCapsRequestsInFlight(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithRateLimits(RateLimit{
+		PathPrefix:  "/api/projects",
+		MaxInFlight: 2,
+	}))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var body string
+			err, _ := client.HttpGetString(server.URL+"/api/projects", &body, []int{200})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxInFlight))
+}
+
+func TestRateLimitWaitRespectsContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version": "2024.1.0"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithRateLimits(RateLimit{RequestsPerSecond: 0.1}))
+	require.NoError(t, err)
+
+	_, err = client.CurrentVersion()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = client.CurrentVersionContext(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+	assert.True(t, time.Since(start) < time.Second)
+
+	// the token of the cancelled request is given back
+	assert.True(t, client.limiters[0].tokens > -1)
+}
+
+func TestRateLimitSlotRespectsContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+	client.SetRateLimits(RateLimit{MaxInFlight: 1})
+
+	var body string
+	go client.HttpGetString(server.URL+"/api/projects", &body, []int{200})
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	var other string
+	err, _ = client.HttpGetStringContext(ctx, server.URL+"/api/projects", &other, []int{200})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "waiting for a free slot")
+}
+
+func TestRateLimitCountsResponsesUntilTheirBodyIsRead(t *testing.T) {
+	finish := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"items": [`))
+		w.(http.Flusher).Flush()
+		if r.URL.Path == "/api/projects/1/versions/1/components" {
+			<-finish
+		}
+		w.Write([]byte(`]}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithRateLimits(RateLimit{MaxInFlight: 1}))
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/projects/1/versions/1/components", nil)
+	require.NoError(t, err)
+	resp, err := client.do(req)
+	require.NoError(t, err)
+
+	// the headers have arrived, but the body is still streaming
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	var body string
+	err, _ = client.HttpGetStringContext(ctx, server.URL+"/api/projects", &body, []int{200})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "waiting for a free slot")
+
+	close(finish)
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	err, _ = client.HttpGetString(server.URL+"/api/projects", &body, []int{200})
+	require.NoError(t, err)
+	resp.Body.Close()
+}
+
+func TestRateLimitReleasesResponsesWithoutResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"name": "demo"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithRateLimits(RateLimit{MaxInFlight: 1}))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, client.HttpGetJSONContext(ctx, server.URL+"/api/projects/1", nil, http.StatusCreated))
+		_, err := client.HttpPostJSONExpectResultContext(ctx, server.URL+"/api/projects", struct{}{}, nil, "application/json", http.StatusCreated)
+		require.NoError(t, err)
+		_, err = client.HttpPostRawJSONExpectResultContext(ctx, server.URL+"/api/projects", strings.NewReader("{}"), nil, "application/json", http.StatusCreated)
+		require.NoError(t, err)
+	}
+}
+
+func TestRateLimitMatchesWholePathSegments(t *testing.T) {
+	limit := newLimiter(RateLimit{PathPrefix: "/api/projects"})
+	for path, matches := range map[string]bool{
+		"/api/projects":            true,
+		"/api/projects/1/versions": true,
+		"/api/projectsX":           false,
+		"/api/project":             false,
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		assert.Equal(t, matches, limit.matches(req), path)
+	}
+
+	assert.True(t, newLimiter(RateLimit{PathPrefix: "/api/"}).matches(httptest.NewRequest(http.MethodGet, "/api/projects", nil)))
+	assert.True(t, newLimiter(RateLimit{}).matches(httptest.NewRequest(http.MethodGet, "/api/projects", nil)))
+}
@@ -0,0 +1,246 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy describes how the Client retries requests that failed with a transient error,
+// such as a 429, 502, 503 or 504 response or a dropped connection.
+// GET, HEAD, PUT, DELETE and OPTIONS requests are retried; POST requests only when RetryPost is set.
+// Requests whose body cannot be replayed are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request, including the first one
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts, unless the server asks for longer via Retry-After
+	MaxBackoff time.Duration
+	// Multiplier is applied to the delay after every retry
+	Multiplier float64
+	// Jitter randomly shortens every delay by up to this fraction of it (0 - 1)
+	Jitter float64
+	// MaxElapsedTime caps the total time spent on a request including all retries, zero means no cap
+	MaxElapsedTime time.Duration
+	// RetryableStatusCodes are the response codes which are retried
+	RetryableStatusCodes []int
+	// RetryPost enables retries of POST requests, which are not idempotent in general
+	RetryPost bool
+}
+
+// DefaultRetryPolicy returns a policy making up to 4 attempts with exponential backoff starting at 500ms,
+// retrying 429, 502, 503 and 504 responses for at most 2 minutes in total
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		MaxElapsedTime: 2 * time.Minute,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// SetRetryPolicy sets the policy used to retry failed requests. A nil policy disables retries.
+func (c *Client) SetRetryPolicy(policy *RetryPolicy) {
+	c.retryPolicy = policy
+}
+
+// allows reports whether the request may be retried at all under this policy
+func (p *RetryPolicy) allows(req *http.Request) bool {
+	if p == nil || p.MaxAttempts <= 1 {
+		return false
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		return p.RetryPost
+	}
+
+	return false
+}
+
+func (p *RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		// the caller gave up, there is no point in trying again
+		return req.Context().Err() == nil
+	}
+
+	for _, code := range p.RetryableStatusCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// delay returns how long to wait before the attempt following the given one
+func (p *RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	if d, ok := retryAfter(resp); ok {
+		return d
+	}
+
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		backoff -= backoff * math.Min(p.Jitter, 1) * rand.Float64()
+	}
+
+	return time.Duration(backoff)
+}
+
+// retryAfter parses the Retry-After header, which holds either a number of seconds or an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	value := resp.Header.Get(HeaderNameRetryAfter)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// doWithRetries sends the request until it succeeds, fails with a non-retryable error
+// or the policy runs out of attempts or time
+func (c *Client) doWithRetries(req *http.Request, policy *RetryPolicy) (*http.Response, error) {
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := withAttempt(req, attempt)
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, attemptsError(err, attempt-1)
+			}
+			attemptReq.Body = body
+		}
+
+		resp, err := c.roundTrip(attemptReq)
+		if attempt >= policy.MaxAttempts || !policy.shouldRetry(req, resp, err) {
+			return resp, attemptsError(err, attempt)
+		}
+
+		delay := policy.delay(attempt, resp)
+		if policy.MaxElapsedTime > 0 && time.Since(start)+delay > policy.MaxElapsedTime {
+			return resp, attemptsError(err, attempt)
+		}
+
+		c.log().Debug("Retrying failed request", methodField(req.Method), urlField(req.URL.String()),
+			statusField(getResponseStatus(resp)), Field{FieldError, err}, Field{"attempt", attempt}, Field{"delay", delay})
+
+		discardResponse(resp)
+
+		if err := sleepContext(req.Context(), delay); err != nil {
+			return nil, attemptsError(err, attempt)
+		}
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// discardResponse drains and closes the response body so that the connection can be reused
+func discardResponse(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64*1024))
+	resp.Body.Close()
+}
+
+type attemptKey struct{}
+
+// withAttempt returns a copy of the request which remembers its attempt number, so that errors built
+// from its response can report it. The headers are copied as well, because the http.Client adds the
+// cookies of its jar to them, and those must not leak into later attempts.
+func withAttempt(req *http.Request, attempt int) *http.Request {
+	return req.Clone(context.WithValue(req.Context(), attemptKey{}, attempt))
+}
+
+// attemptsOf returns the number of attempts it took to get the response, or 0 if unknown
+func attemptsOf(resp *http.Response) int {
+	if resp == nil || resp.Request == nil {
+		return 0
+	}
+
+	attempt, _ := resp.Request.Context().Value(attemptKey{}).(int)
+	return attempt
+}
+
+func attemptsError(err error, attempts int) error {
+	if err == nil || attempts <= 1 {
+		return err
+	}
+
+	return &HubClientError{
+		Err:      errors.WithMessagef(err, "giving up after %d attempts", attempts),
+		Attempts: attempts,
+	}
+}
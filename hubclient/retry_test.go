@@ -0,0 +1,167 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return policy
+}
+
+func newRetryTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewWithClient(server.URL, 0, server.Client())
+	require.NoError(t, err)
+	client.SetRetryPolicy(testRetryPolicy())
+
+	return client, server
+}
+
+func TestClient_RetriesTransientGetFailures(t *testing.T) {
+	var requests int32
+	client, server := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"version": "2024.1.0"}`))
+	})
+
+	var version hubapi.CurrentVersion
+	err := client.HttpGetJSON(server.URL+hubapi.CurrentVersionApi, &version, http.StatusOK)
+
+	require.NoError(t, err)
+	assert.Equal(t, "2024.1.0", version.Version)
+	assert.EqualValues(t, 3, requests)
+}
+
+func TestClient_RetryReplaysPutBody(t *testing.T) {
+	var requests int32
+	var lastBody string
+	client, server := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		lastBody = string(body)
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	err := client.HttpPutString(server.URL, "chunk", "text/plain", http.StatusAccepted)
+
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, requests)
+	assert.Equal(t, "chunk", lastBody)
+}
+
+func TestClient_RetryGivesUpAndRecordsAttempts(t *testing.T) {
+	var requests int32
+	client, server := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	err := client.HttpGetJSON(server.URL, &hubapi.CurrentVersion{}, http.StatusOK)
+
+	require.Error(t, err)
+	hce, ok := err.(*HubClientError)
+	require.True(t, ok, "unexpected error type %T", err)
+	assert.Equal(t, http.StatusServiceUnavailable, hce.StatusCode)
+	assert.Equal(t, 4, hce.Attempts)
+	assert.EqualValues(t, 4, requests)
+	assert.Contains(t, err.Error(), "after 4 attempts")
+}
+
+func TestClient_RetryDoesNotRetryPostByDefault(t *testing.T) {
+	var requests int32
+	client, server := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	_, err := client.HttpPostString(server.URL, "{}", "application/json", http.StatusCreated)
+	require.Error(t, err)
+	assert.EqualValues(t, 1, requests)
+
+	client.retryPolicy.RetryPost = true
+	_, err = client.HttpPostString(server.URL, "{}", "application/json", http.StatusCreated)
+	require.Error(t, err)
+	assert.EqualValues(t, 5, requests)
+}
+
+func TestClient_RetryHonoursRetryAfter(t *testing.T) {
+	var requests int32
+	client, server := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set(HeaderNameRetryAfter, "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+
+	start := time.Now()
+	err := client.HttpGetJSON(server.URL, &hubapi.CurrentVersion{}, http.StatusOK)
+
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, requests)
+	assert.True(t, time.Since(start) >= time.Second, "Retry-After was not honoured")
+}
+
+func TestClient_RetryStopsAtMaxElapsedTime(t *testing.T) {
+	var requests int32
+	client, server := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set(HeaderNameRetryAfter, "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	client.retryPolicy.MaxElapsedTime = time.Second
+
+	err := client.HttpGetJSON(server.URL, &hubapi.CurrentVersion{}, http.StatusOK)
+
+	require.Error(t, err)
+	assert.EqualValues(t, 1, requests)
+}
+
+func TestRetryPolicy_DelayIsCapped(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 3 * time.Second, Multiplier: 2}
+
+	assert.Equal(t, time.Second, policy.delay(1, nil))
+	assert.Equal(t, 2*time.Second, policy.delay(2, nil))
+	assert.Equal(t, 3*time.Second, policy.delay(3, nil))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		d := policy.delay(2, nil)
+		assert.True(t, d > time.Second && d <= 2*time.Second, "delay %s out of range", d)
+	}
+}
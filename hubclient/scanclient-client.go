@@ -15,6 +15,7 @@
 package hubclient
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -22,25 +23,48 @@ import (
 )
 
 func (c *Client) DownloadScanClientMac(path string) error {
-	return c.downloadScanClientHelper(path, "download/scan.cli-macosx.zip")
+	return c.DownloadScanClientMacContext(context.Background(), path)
+}
+
+func (c *Client) DownloadScanClientMacContext(ctx context.Context, path string) error {
+	return c.downloadScanClientHelper(ctx, path, "download/scan.cli-macosx.zip")
 }
 
 func (c *Client) DownloadScanClientLinux(path string) error {
-	return c.downloadScanClientHelper(path, "download/scan.cli.zip")
+	return c.DownloadScanClientLinuxContext(context.Background(), path)
+}
+
+func (c *Client) DownloadScanClientLinuxContext(ctx context.Context, path string) error {
+	return c.downloadScanClientHelper(ctx, path, "download/scan.cli.zip")
 }
 
 func (c *Client) DownloadScanClientWindows(path string) error {
-	return c.downloadScanClientHelper(path, "download/scan.cli-windows.zip")
+	return c.DownloadScanClientWindowsContext(context.Background(), path)
+}
+
+func (c *Client) DownloadScanClientWindowsContext(ctx context.Context, path string) error {
+	return c.downloadScanClientHelper(ctx, path, "download/scan.cli-windows.zip")
 }
 
-func (c *Client) downloadScanClientHelper(path string, urlPath string) error {
+func (c *Client) downloadScanClientHelper(ctx context.Context, path string, urlPath string) error {
 
 	scanClientURL := fmt.Sprintf("%s/%s", c.baseURL, urlPath)
 
-	resp, err := c.httpClient.Get(scanClientURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scanClientURL, nil)
+	if err != nil {
+		return AnnotateHubClientErrorf(err, "unable to create request for %s", urlPath)
+	}
+
+	c.applyHeaderValues(req, nil)
+
+	// the archive goes straight to a file, so it may well be larger than any JSON response
+	req = withoutResponseLimit(req)
+
+	resp, err := c.do(req)
 	if err != nil {
 		return AnnotateHubClientErrorf(err, "unable to http GET %s", urlPath)
 	} else if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
 		return HubClientStatusCodeErrorf(resp.StatusCode, "GET failed: received status != 200 from %s: %s", scanClientURL, resp.Status)
 	}
 
@@ -1,18 +1,22 @@
 package hubclient
 
 import (
+	"context"
 	"github.com/blackducksoftware/hub-client-go/hubapi"
-	log "github.com/sirupsen/logrus"
 	"io"
 	"net/http"
 )
 
 func (c *Client) StartSnippetScan(content io.Reader) (*hubapi.SnippetMatchResponse, error) {
+	return c.StartSnippetScanContext(context.Background(), content)
+}
+
+func (c *Client) StartSnippetScanContext(ctx context.Context, content io.Reader) (*hubapi.SnippetMatchResponse, error) {
 	snippetMatchingURL := hubapi.BuildUrl(c.baseURL, hubapi.SnippetMatchingApi)
 	var result hubapi.SnippetMatchResponse
-	_, err := c.HttpPostRawJSONExpectResult(snippetMatchingURL, content, &result, "text/plain", http.StatusOK)
+	_, err := c.HttpPostRawJSONExpectResultContext(ctx, snippetMatchingURL, content, &result, "text/plain", http.StatusOK)
 	if err != nil {
-		log.Error("Error kicking off a snippet scan.", err)
+		c.log().Error("Error kicking off a snippet scan", urlField(snippetMatchingURL), errorField(err))
 		return nil, TraceHubClientError(err)
 	}
 
@@ -14,14 +14,22 @@
 
 package hubclient
 
-import "github.com/blackducksoftware/hub-client-go/hubapi"
+import (
+	"context"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+)
 
 func (c *Client) SsoStatus() (*hubapi.SsoStatus, error) {
+	return c.SsoStatusContext(context.Background())
+}
+
+func (c *Client) SsoStatusContext(ctx context.Context) (*hubapi.SsoStatus, error) {
 
 	ssoStausURL := hubapi.BuildUrl(c.baseURL, hubapi.SsoStatusApi)
 
 	var ssoStatus hubapi.SsoStatus
-	err := c.HttpGetJSON(ssoStausURL, &ssoStatus, 200)
+	err := c.HttpGetJSONContext(ctx, ssoStausURL, &ssoStatus, 200)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to get SSO status")
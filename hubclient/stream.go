@@ -0,0 +1,369 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/pkg/errors"
+)
+
+// SetMaxResponseSize limits the size of the response bodies read by the Client to size bytes. Reading a larger
+// body fails with an error matching ErrResponseTooLarge instead of holding all of it in memory.
+// The limit applies to every response read into memory or decoded, including the ones read by an ItemStream
+// and error responses, but not to the scan client archives of DownloadScanClient and friends, which are
+// written to a file as they arrive. Zero, the default, means no limit.
+func (c *Client) SetMaxResponseSize(size int64) {
+	c.maxResponseSize = size
+}
+
+// WithMaxResponseSize sets the maximum size of the response bodies read by the Client, see SetMaxResponseSize
+func WithMaxResponseSize(size int64) Option {
+	return func(o *clientOptions) error {
+		if size < 0 {
+			return HubClientErrorf("the maximum response size cannot be negative: %d", size)
+		}
+		o.maxResponseSize = size
+		return nil
+	}
+}
+
+type unlimitedResponseKey struct{}
+
+// withoutResponseLimit returns a copy of req whose response is not subject to the maximum response size
+func withoutResponseLimit(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), unlimitedResponseKey{}, true))
+}
+
+// limitResponse makes reading the body of resp, the response to req, fail once it exceeds the maximum
+// response size
+func (c *Client) limitResponse(req *http.Request, resp *http.Response) *http.Response {
+	if req.Context().Value(unlimitedResponseKey{}) != nil {
+		return resp
+	}
+
+	if limit := c.maxResponseSize; limit > 0 && resp != nil && resp.Body != nil {
+		tooLarge := resp.ContentLength > limit
+		if tooLarge {
+			// the body is not read anyway, and the size it claims must not make anyone allocate it up front
+			resp.ContentLength = -1
+		}
+		resp.Body = &limitedReadCloser{ReadCloser: resp.Body, limit: limit, remaining: limit, tooLarge: tooLarge}
+	}
+
+	return resp
+}
+
+// limitedReadCloser fails with ErrResponseTooLarge once more than limit bytes are read
+type limitedReadCloser struct {
+	io.ReadCloser
+	limit     int64
+	remaining int64
+	// tooLarge is set when the size of the body is known to exceed the limit up front
+	tooLarge bool
+}
+
+func (r *limitedReadCloser) Read(p []byte) (int, error) {
+	if r.tooLarge || r.remaining < 0 {
+		return 0, errors.WithMessagef(ErrResponseTooLarge, "the response body exceeds the maximum size of %d bytes", r.limit)
+	}
+
+	// read one byte more than allowed to tell a body of exactly the limit from a larger one
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	n, err := r.ReadCloser.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		n += int(r.remaining)
+		return n, errors.WithMessagef(ErrResponseTooLarge, "the response body exceeds the maximum size of %d bytes", r.limit)
+	}
+
+	return n, err
+}
+
+// ItemStream iterates over the items of a paged list like a Pager, but decodes the items of a page one at a
+// time while reading the response. Only the current item is held in memory, which makes it suitable for
+// pages with many large items, such as BOM components or the full result of a rapid scan:
+//
+//	stream := client.ProjectVersionComponentsStream(link, &hubapi.GetListOptions{Limit: &limit})
+//	defer stream.Close()
+//	for stream.Next() {
+//		component := stream.Item()
+//		...
+//	}
+//	if err := stream.Err(); err != nil {
+//		...
+//	}
+//
+// The response of a page stays open until all of its items are read or the ItemStream is closed.
+// An ItemStream must not be used by several goroutines at once.
+type ItemStream[T any] struct {
+	ctx       context.Context
+	c         *Client
+	link      string
+	options   *hubapi.GetListOptions
+	mimetypes []string
+
+	body    io.ReadCloser
+	decoder *json.Decoder
+	// inItems is set while the decoder is inside the items array of the current page
+	inItems bool
+	// pageItems is the number of items read from the current page
+	pageItems int
+	total     int
+	fetched   bool
+	item      T
+	err       error
+	closed    bool
+}
+
+// NewItemStream creates an ItemStream over the items of type T of the list at link. The pages are requested
+// with the given media types, or the one of T if none are given. Paging starts at the offset of options and
+// fetches pages of its limit, 100 items if not set. The options are not modified.
+func NewItemStream[T any](ctx context.Context, c *Client, link string, options *hubapi.GetListOptions, mimetypes ...string) *ItemStream[T] {
+	if len(mimetypes) == 0 {
+		var item T
+		if t := reflect.TypeOf(item); t != nil && t.Kind() == reflect.Struct {
+			if mimetype := hubapi.GetMimeType(&item); mimetype != "" {
+				mimetypes = []string{mimetype}
+			}
+		}
+	}
+
+	return &ItemStream[T]{
+		ctx:       ctx,
+		c:         c,
+		link:      link,
+		options:   copyListOptions(options),
+		mimetypes: mimetypes,
+	}
+}
+
+// Next decodes the next item, requesting the next page if necessary. It returns false once all items
+// have been read, the ItemStream was closed or reading a page failed, see Err.
+func (s *ItemStream[T]) Next() bool {
+	if s.closed || s.err != nil {
+		return false
+	}
+
+	for {
+		if s.decoder == nil && !s.fetchNext() {
+			return false
+		}
+
+		more, err := s.decodeItem()
+		if err != nil {
+			s.fail(AnnotateHubClientErrorf(err, "error parsing list %s", s.link))
+			return false
+		}
+
+		if more {
+			s.pageItems++
+			return true
+		}
+
+		s.closeBody()
+		s.options.NextPage()
+
+		// an empty page ends the paging even if the total count promises more
+		if s.pageItems == 0 {
+			return false
+		}
+	}
+}
+
+// Item returns the current item, the zero value before the first call to Next
+func (s *ItemStream[T]) Item() T {
+	return s.item
+}
+
+// Err returns the error which stopped the paging, if any
+func (s *ItemStream[T]) Err() error {
+	return s.err
+}
+
+// Total returns the total number of items in the list as reported by the last page read. Black Duck reports
+// it ahead of the items, in which case it is known once the first item of a page has been read.
+func (s *ItemStream[T]) Total() int {
+	return s.total
+}
+
+// Close stops the paging, closing the response being read
+func (s *ItemStream[T]) Close() error {
+	s.closed = true
+	s.closeBody()
+
+	var zero T
+	s.item = zero
+
+	return nil
+}
+
+func (s *ItemStream[T]) fetchNext() bool {
+	if s.fetched && *s.options.Offset >= s.total {
+		return false
+	}
+
+	if err := s.ctx.Err(); err != nil {
+		s.err = AnnotateHubClientErrorf(err, "paging of %s was interrupted", s.link)
+		return false
+	}
+
+	pageURL, err := listURL(s.link, s.options)
+	if err != nil {
+		s.err = AnnotateHubClientErrorf(err, "invalid list URL %s", s.link)
+		return false
+	}
+
+	err, resp := s.c.httpGetBody(s.ctx, pageURL, []int{http.StatusOK}, s.mimetypes...)
+	if err != nil {
+		s.err = AnnotateHubClientErrorf(err, "Error trying to retrieve list %s", s.link)
+		return false
+	}
+
+	s.fetched = true
+	s.body = resp.Body
+	s.decoder = json.NewDecoder(resp.Body)
+	s.pageItems = 0
+
+	if err := expectDelim(s.decoder, '{'); err != nil {
+		s.fail(AnnotateHubClientErrorf(err, "error parsing list %s", s.link))
+		return false
+	}
+
+	return true
+}
+
+// decodeItem decodes the next item of the current page, skipping over the other fields of the list.
+// It returns false once the end of the page is reached.
+func (s *ItemStream[T]) decodeItem() (bool, error) {
+	if s.inItems {
+		if s.decoder.More() {
+			var item T
+			if err := s.decoder.Decode(&item); err != nil {
+				return false, err
+			}
+			s.item = item
+			return true, nil
+		}
+
+		s.inItems = false
+		if err := expectDelim(s.decoder, ']'); err != nil {
+			return false, err
+		}
+	}
+
+	for s.decoder.More() {
+		key, err := s.decoder.Token()
+		if err != nil {
+			return false, err
+		}
+
+		switch key {
+		case "totalCount":
+			if err := s.decoder.Decode(&s.total); err != nil {
+				return false, err
+			}
+		case "items":
+			if err := expectDelim(s.decoder, '['); err != nil {
+				return false, err
+			}
+			s.inItems = true
+			return s.decodeItem()
+		default:
+			var skipped json.RawMessage
+			if err := s.decoder.Decode(&skipped); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return false, expectDelim(s.decoder, '}')
+}
+
+func (s *ItemStream[T]) fail(err error) {
+	s.err = err
+	s.closeBody()
+}
+
+func (s *ItemStream[T]) closeBody() {
+	if s.body != nil {
+		s.body.Close()
+	}
+	s.body = nil
+	s.decoder = nil
+	s.inItems = false
+}
+
+// expectDelim reads the next token of decoder, which must be the delimiter
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if token != delim {
+		return errors.Errorf("expected %v but found %v", delim, token)
+	}
+
+	return nil
+}
+
+// listURL adds the paging parameters of options to the query of link
+func listURL(link string, options *hubapi.GetListOptions) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	for key, value := range options.Parameters() {
+		query.Set(key, value)
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// ProjectVersionComponentsStream returns an ItemStream over the BOM components at link, the components link of a project version
+func (c *Client) ProjectVersionComponentsStream(link hubapi.ResourceLink, options *hubapi.GetListOptions) *ItemStream[hubapi.BomComponent] {
+	return c.ProjectVersionComponentsStreamContext(context.Background(), link, options)
+}
+
+func (c *Client) ProjectVersionComponentsStreamContext(ctx context.Context, link hubapi.ResourceLink, options *hubapi.GetListOptions) *ItemStream[hubapi.BomComponent] {
+	return NewItemStream[hubapi.BomComponent](ctx, c, link.Href, options)
+}
+
+// RapidScanComponentsStream returns an ItemStream over the full result of a finished rapid scan, see PollRapidScanResults.
+// The pages hold pageLimit components.
+func (c *Client) RapidScanComponentsStream(rapidScanEndpoint string, pageLimit int, option RapidScanOpts) *ItemStream[hubapi.RapidScanComponent] {
+	return c.RapidScanComponentsStreamContext(context.Background(), rapidScanEndpoint, pageLimit, option)
+}
+
+func (c *Client) RapidScanComponentsStreamContext(ctx context.Context, rapidScanEndpoint string, pageLimit int, option RapidScanOpts) *ItemStream[hubapi.RapidScanComponent] {
+	link := hubapi.BuildUrl(rapidScanEndpoint, hubapi.FullResultsApi)
+	link = hubapi.AddParameters(link, map[string]string{"nonVulnerableComponents": strconv.FormatBool(option.IncludeNonVulnerableComponents)})
+
+	return NewItemStream[hubapi.RapidScanComponent](ctx, c, link, &hubapi.GetListOptions{Limit: &pageLimit}, hubapi.ContentTypeRapidScanResults)
+}
@@ -0,0 +1,221 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// componentsServer serves a list of total BOM components, with the total count after the items
+// and an unknown field before them
+func componentsServer(t *testing.T, total int, opts ...Option) (*Client, *[]*http.Request) {
+	var lock sync.Mutex
+	var requests []*http.Request
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests = append(requests, r)
+		lock.Unlock()
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		var items []string
+		for i := offset; i < offset+limit && i < total; i++ {
+			items = append(items, fmt.Sprintf(`{"componentName": "component-%d", "origins": [{"name": "origin"}]}`, i))
+		}
+
+		w.Header().Set(HeaderNameContentType, "application/json")
+		fmt.Fprintf(w, `{"appliedFilters": [{"name": "x"}], "items": [%s], "totalCount": %d, "_meta": {"href": "%s"}}`,
+			strings.Join(items, ","), total, r.URL)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL, append([]Option{WithHTTPClient(server.Client())}, opts...)...)
+	require.NoError(t, err)
+	return client, &requests
+}
+
+func TestItemStream(t *testing.T) {
+	client, requests := componentsServer(t, 5)
+
+	limit := 2
+	stream := client.ProjectVersionComponentsStream(hubapi.ResourceLink{Href: client.BaseURL() + "/api/projects/1/versions/2/components?filter=a"},
+		&hubapi.GetListOptions{Limit: &limit})
+	defer stream.Close()
+
+	var names []string
+	for stream.Next() {
+		names = append(names, stream.Item().ComponentName)
+		assert.Len(t, stream.Item().Origins, 1)
+	}
+	require.NoError(t, stream.Err())
+
+	assert.Equal(t, []string{"component-0", "component-1", "component-2", "component-3", "component-4"}, names)
+	assert.Equal(t, 5, stream.Total())
+
+	require.Len(t, *requests, 3)
+	for i, r := range *requests {
+		assert.Equal(t, "a", r.URL.Query().Get("filter"))
+		assert.Equal(t, strconv.Itoa(2*i), r.URL.Query().Get("offset"))
+		assert.Equal(t, hubapi.GetMimeType(&hubapi.BomComponent{}), r.Header.Get(HeaderNameAccept))
+	}
+}
+
+func TestItemStreamEmptyList(t *testing.T) {
+	client, requests := componentsServer(t, 0)
+
+	stream := NewItemStream[hubapi.BomComponent](context.Background(), client, client.BaseURL()+"/api/components", nil)
+	assert.False(t, stream.Next())
+	assert.NoError(t, stream.Err())
+	assert.Len(t, *requests, 1)
+}
+
+func TestItemStreamClose(t *testing.T) {
+	client, requests := componentsServer(t, 10)
+
+	stream := NewItemStream[hubapi.BomComponent](context.Background(), client, client.BaseURL()+"/api/components", nil)
+	require.True(t, stream.Next())
+	require.NoError(t, stream.Close())
+
+	assert.False(t, stream.Next())
+	assert.Equal(t, hubapi.BomComponent{}, stream.Item())
+	assert.Len(t, *requests, 1)
+}
+
+func TestItemStreamErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		items int
+	}{
+		{"not an object", `[]`, 0},
+		{"items not an array", `{"items": {}}`, 0},
+		{"malformed item", `{"items": [{"userName": "a"}, {"userName": 1}]}`, 1},
+		{"truncated", `{"items": [{"userName": "a"}, {"userName"`, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := statusServer(t, http.StatusOK, test.body)
+
+			stream := NewItemStream[hubapi.User](context.Background(), client, client.BaseURL()+"/api/users", nil)
+			items := 0
+			for stream.Next() {
+				items++
+			}
+
+			assert.Equal(t, test.items, items)
+			assert.Error(t, stream.Err())
+		})
+	}
+
+	client := statusServer(t, http.StatusNotFound, `{}`)
+	stream := NewItemStream[hubapi.User](context.Background(), client, client.BaseURL()+"/api/users", nil)
+	assert.False(t, stream.Next())
+	assert.True(t, errors.Is(stream.Err(), ErrNotFound))
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	body := `{"name": "` + strings.Repeat("x", 200) + `"}`
+
+	client := statusServer(t, http.StatusOK, body)
+	client.SetMaxResponseSize(int64(len(body)))
+	_, err := client.GetProject(hubapi.ResourceLink{Href: client.BaseURL() + "/api/projects/1"})
+	require.NoError(t, err)
+
+	client.SetMaxResponseSize(100)
+	_, err = client.GetProject(hubapi.ResourceLink{Href: client.BaseURL() + "/api/projects/1"})
+	assert.True(t, errors.Is(err, ErrResponseTooLarge))
+
+	var text string
+	err, status := client.HttpGetString(client.BaseURL()+"/api/projects/1", &text, []int{http.StatusOK})
+	assert.True(t, errors.Is(err, ErrResponseTooLarge))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, text)
+
+	// scan client archives go to a file and are not limited
+	archive := filepath.Join(t.TempDir(), "scan.cli.zip")
+	require.NoError(t, client.DownloadScanClientLinux(archive))
+	downloaded, err := os.ReadFile(archive)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(downloaded))
+
+	_, err = New(client.BaseURL(), WithMaxResponseSize(-1))
+	assert.Error(t, err)
+}
+
+func TestMaxResponseSizeOfLargeDeclaredResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a body far larger than could be allocated, of which the server only ever sends the beginning
+		w.Header().Set("Content-Length", strconv.FormatInt(1<<50, 10))
+		w.Write([]byte(`{"name": "`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithMaxResponseSize(1000),
+		WithRateLimits(RateLimit{MaxInFlight: 1}), WithMetrics(&recordingMetrics{}))
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = client.GetProject(hubapi.ResourceLink{Href: server.URL + "/api/projects/1"})
+		assert.True(t, errors.Is(err, ErrResponseTooLarge), "got %v", err)
+	}
+}
+
+func TestMaxResponseSizeOfChunkedResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// flushing before the end makes the response chunked, without a content length
+		w.Write([]byte(`{"items": [`))
+		w.(http.Flusher).Flush()
+		for i := 0; i < 100; i++ {
+			if i > 0 {
+				w.Write([]byte(","))
+			}
+			fmt.Fprintf(w, `{"userName": "user-%d"}`, i)
+		}
+		w.Write([]byte(`], "totalCount": 100}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()), WithMaxResponseSize(1000), WithMetrics(&recordingMetrics{}))
+	require.NoError(t, err)
+
+	_, err = client.ListUsers(nil)
+	assert.True(t, errors.Is(err, ErrResponseTooLarge))
+
+	stream := NewItemStream[hubapi.User](context.Background(), client, server.URL+"/api/users", nil)
+	items := 0
+	for stream.Next() {
+		items++
+	}
+	assert.True(t, errors.Is(stream.Err(), ErrResponseTooLarge))
+	assert.Greater(t, items, 10)
+	assert.Less(t, items, 100)
+}
@@ -0,0 +1,139 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TLSOptions configures how the Client verifies the Black Duck server and how it authenticates itself to it.
+// The zero value verifies the server against the system roots and requires TLS 1.2 or newer.
+type TLSOptions struct {
+	// CAFile is a PEM bundle of certificate authorities to trust instead of the system roots
+	CAFile string
+	// CAPool is a pool of certificate authorities to trust instead of the system roots.
+	// If CAFile is set as well, its certificates are added to this pool.
+	CAPool *x509.CertPool
+	// ClientCertFile and ClientKeyFile are a PEM certificate and key presented for mutual TLS
+	ClientCertFile string
+	ClientKeyFile  string
+	// ClientCertificates are presented for mutual TLS, in addition to ClientCertFile
+	ClientCertificates []tls.Certificate
+	// MinVersion is the minimum TLS version accepted, tls.VersionTLS12 if not set
+	MinVersion uint16
+	// PinnedPublicKeys are base64 encoded SHA-256 hashes of the SubjectPublicKeyInfo of certificates
+	// of which at least one must be in the server's chain, optionally prefixed with "sha256/"
+	PinnedPublicKeys []string
+	// InsecureSkipVerify disables verification of the server certificate chain and host name.
+	// Pinned public keys are still checked.
+	InsecureSkipVerify bool
+}
+
+// TLSConfig builds the tls.Config described by the options
+func (o *TLSOptions) TLSConfig() (*tls.Config, error) {
+	if o == nil {
+		o = &TLSOptions{}
+	}
+
+	config := &tls.Config{
+		MinVersion:         o.MinVersion,
+		RootCAs:            o.CAPool,
+		Certificates:       o.ClientCertificates,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+
+	if config.MinVersion == 0 {
+		config.MinVersion = tls.VersionTLS12
+	}
+
+	if o.CAFile != "" {
+		pem, err := ioutil.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, AnnotateHubClientErrorf(err, "unable to read CA file %s", o.CAFile)
+		}
+
+		if config.RootCAs == nil {
+			config.RootCAs = x509.NewCertPool()
+		}
+
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, HubClientErrorf("no certificates found in CA file %s", o.CAFile)
+		}
+	}
+
+	if o.ClientCertFile != "" || o.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.ClientCertFile, o.ClientKeyFile)
+		if err != nil {
+			return nil, AnnotateHubClientError(err, "unable to load client certificate")
+		}
+
+		config.Certificates = append(append([]tls.Certificate{}, config.Certificates...), cert)
+	}
+
+	if len(o.PinnedPublicKeys) > 0 {
+		config.VerifyConnection = verifyPinnedPublicKeys(o.PinnedPublicKeys)
+	}
+
+	return config, nil
+}
+
+// verifyPinnedPublicKeys returns a check that at least one certificate presented by the server has a pinned public key
+func verifyPinnedPublicKeys(pins []string) func(tls.ConnectionState) error {
+	pinned := make(map[string]bool, len(pins))
+	for _, pin := range pins {
+		pinned[strings.TrimPrefix(pin, "sha256/")] = true
+	}
+
+	return func(state tls.ConnectionState) error {
+		for _, cert := range state.PeerCertificates {
+			sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+			if pinned[base64.StdEncoding.EncodeToString(sum[:])] {
+				return nil
+			}
+		}
+
+		return errors.Errorf("none of the certificates presented by %s has a pinned public key", state.ServerName)
+	}
+}
+
+// NewHTTPClient creates an http.Client suitable for the Client constructors which take one,
+// with the given timeout and TLS options. Proxy settings are taken from the environment.
+func NewHTTPClient(timeout time.Duration, tlsOptions *TLSOptions) (*http.Client, error) {
+	tlsConfig, err := tlsOptions.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	tr := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	}
+
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: tr,
+	}
+
+	return client, nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTLSTestServer(t *testing.T) *httptest.Server {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version": "2024.1.0"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func getVersion(t *testing.T, server *httptest.Server, options *TLSOptions) error {
+	httpClient, err := NewHTTPClient(5*time.Second, options)
+	require.NoError(t, err)
+
+	client, err := NewWithClient(server.URL, 0, httpClient)
+	require.NoError(t, err)
+
+	_, err = client.CurrentVersion()
+	return err
+}
+
+func serverCertPool(server *httptest.Server) *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	return pool
+}
+
+func TestNewHTTPClient_VerifiesServerByDefault(t *testing.T) {
+	server := newTLSTestServer(t)
+
+	err := getVersion(t, server, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate")
+
+	assert.NoError(t, getVersion(t, server, &TLSOptions{CAPool: serverCertPool(server)}))
+	assert.NoError(t, getVersion(t, server, &TLSOptions{InsecureSkipVerify: true}))
+}
+
+func TestNewHTTPClient_DefaultHttpClientVerifiesServer(t *testing.T) {
+	server := newTLSTestServer(t)
+
+	client, err := NewWithSession(server.URL, 0, 5*time.Second)
+	require.NoError(t, err)
+
+	_, err = client.CurrentVersion()
+	assert.Error(t, err)
+}
+
+func TestNewHTTPClient_LoadsCAFile(t *testing.T) {
+	server := newTLSTestServer(t)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, ioutil.WriteFile(caFile, certPEM, 0600))
+
+	assert.NoError(t, getVersion(t, server, &TLSOptions{CAFile: caFile}))
+
+	_, err := NewHTTPClient(time.Second, &TLSOptions{CAFile: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.Error(t, err)
+}
+
+func TestNewHTTPClient_PinsPublicKeys(t *testing.T) {
+	server := newTLSTestServer(t)
+
+	sum := sha256.Sum256(server.Certificate().RawSubjectPublicKeyInfo)
+	pin := "sha256/" + base64.StdEncoding.EncodeToString(sum[:])
+
+	assert.NoError(t, getVersion(t, server, &TLSOptions{CAPool: serverCertPool(server), PinnedPublicKeys: []string{pin}}))
+	assert.NoError(t, getVersion(t, server, &TLSOptions{InsecureSkipVerify: true, PinnedPublicKeys: []string{pin}}))
+
+	wrongPin := base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))
+	err := getVersion(t, server, &TLSOptions{InsecureSkipVerify: true, PinnedPublicKeys: []string{wrongPin}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pinned public key")
+}
+
+func TestNewHTTPClient_PresentsClientCertificate(t *testing.T) {
+	clientCert := newClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert.Leaf)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version": "2024.1.0"}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	assert.Error(t, getVersion(t, server, &TLSOptions{CAPool: serverCertPool(server)}))
+	assert.NoError(t, getVersion(t, server, &TLSOptions{
+		CAPool:             serverCertPool(server),
+		ClientCertificates: []tls.Certificate{clientCert},
+		MinVersion:         tls.VersionTLS13,
+	}))
+}
+
+func TestTLSOptions_MinVersion(t *testing.T) {
+	config, err := (&TLSOptions{}).TLSConfig()
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+	assert.False(t, config.InsecureSkipVerify)
+
+	config, err = (&TLSOptions{MinVersion: tls.VersionTLS13}).TLSConfig()
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), config.MinVersion)
+}
+
+func newClientCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "hub-client-go test client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
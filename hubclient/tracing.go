@@ -0,0 +1,143 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "github.com/blackducksoftware/hub-client-go/hubclient"
+
+// Span attributes specific to Black Duck
+const (
+	AttributeLogRef = attribute.Key("blackduck.log_ref")
+)
+
+// SetTracerProvider makes the Client create its spans with the given provider: one for every request and
+// one for every operation made up of several requests, like ForEachPage, PollRapidScanResults and
+// UploadBdioFilesByChunk. The trace context is passed on to the server with the global propagator.
+// Without a provider no spans are created.
+func (c *Client) SetTracerProvider(provider trace.TracerProvider) {
+	if provider == nil {
+		provider = noop.NewTracerProvider()
+	}
+
+	c.tracer = provider.Tracer(tracerName)
+}
+
+// WithTracerProvider sets the provider of the tracer creating the spans of the Client, see SetTracerProvider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(o *clientOptions) error {
+		o.tracerProvider = provider
+		return nil
+	}
+}
+
+func (c *Client) startSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	tracer := c.tracer
+	if tracer == nil {
+		tracer = noop.NewTracerProvider().Tracer(tracerName)
+	}
+
+	return tracer.Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// doTraced sends the request within a client span describing it
+func (c *Client) doTraced(req *http.Request, do DoFunc) (*http.Response, error) {
+	route := routeTemplate(req.URL.Path)
+	ctx, span := c.startSpan(req.Context(), req.Method+" "+route,
+		attribute.String("http.request.method", req.Method),
+		attribute.String("http.route", route),
+		attribute.String("url.full", req.URL.String()))
+
+	if !span.IsRecording() {
+		span.End()
+		return do(req)
+	}
+
+	req = req.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := do(req)
+
+	attempts := attemptsOf(resp)
+	var hce *HubClientError
+	if errors.As(err, &hce) && hce.Attempts > attempts {
+		attempts = hce.Attempts
+	}
+	if attempts > 1 {
+		span.SetAttributes(attribute.Int("http.request.resend_count", attempts-1))
+	}
+
+	if resp != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= http.StatusBadRequest {
+			if logRef := peekLogRef(resp); logRef != "" {
+				span.SetAttributes(AttributeLogRef.String(logRef))
+			}
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+
+	endSpan(span, err)
+
+	return resp, err
+}
+
+// peekLogRef reads the log reference from the error in the body of the response, leaving the body to be read again
+func peekLogRef(resp *http.Response) string {
+	if resp.Body == nil {
+		return ""
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+
+	if err != nil {
+		return ""
+	}
+
+	var hre HubResponseError
+	if json.Unmarshal(body, &hre) != nil {
+		return ""
+	}
+
+	return hre.LogRef
+}
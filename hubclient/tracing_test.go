@@ -0,0 +1,157 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attributes := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+	return attributes
+}
+
+func newTracedClient(t *testing.T, handler http.Handler, opts ...Option) (*Client, *tracetest.SpanRecorder, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	client, err := New(server.URL, append([]Option{WithHTTPClient(server.Client()), WithTracerProvider(provider)}, opts...)...)
+	require.NoError(t, err)
+
+	return client, recorder, server
+}
+
+func TestTracingRequestSpan(t *testing.T) {
+	propagator := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagator)
+
+	client, recorder, server := newTracedClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEmpty(t, r.Header.Get("Traceparent"), "no trace context propagated")
+		w.Write([]byte(`{"name": "project"}`))
+	}))
+
+	_, err := client.GetProject(hubapi.ResourceLink{Href: server.URL + "/api/projects/0c8c9d73-6f9b-4e67-a3f4-0c2e7c6c1a11"})
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /api/projects/{id}", spans[0].Name())
+
+	attributes := spanAttributes(spans[0])
+	assert.Equal(t, "GET", attributes["http.request.method"].AsString())
+	assert.Equal(t, "/api/projects/{id}", attributes["http.route"].AsString())
+	assert.Equal(t, int64(200), attributes["http.response.status_code"].AsInt64())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+}
+
+func TestTracingErrorSpan(t *testing.T) {
+	calls := 0
+	client, recorder, server := newTracedClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errorMessage": "no such project", "errorCode": "{central.constraint_violation.project_not_found}", "logRef": "ab12cd34"}`))
+	}), WithRetryPolicy(&RetryPolicy{MaxAttempts: 2, RetryableStatusCodes: []int{http.StatusServiceUnavailable}}))
+
+	_, err := client.GetProject(hubapi.ResourceLink{Href: server.URL + "/api/projects/1"})
+	require.Error(t, err)
+	assert.Equal(t, "ab12cd34", err.(*HubClientError).HubError.LogRef, "the body was not left to be read")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+
+	attributes := spanAttributes(spans[0])
+	assert.Equal(t, int64(404), attributes["http.response.status_code"].AsInt64())
+	assert.Equal(t, "ab12cd34", attributes[AttributeLogRef].AsString())
+	assert.Equal(t, int64(1), attributes["http.request.resend_count"].AsInt64())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestTracingCompositeSpans(t *testing.T) {
+	client, recorder, server := newTracedClient(t, &projectsServer{total: 5})
+
+	var list hubapi.ProjectList
+	err := client.ForEachPage(server.URL+hubapi.ProjectsApi, pageOptions(2, 0), &list, func() error { return nil })
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+
+	parent := spans[3]
+	assert.Equal(t, "ForEachPage", parent.Name())
+	for _, span := range spans[:3] {
+		assert.Equal(t, "GET "+hubapi.ProjectsApi, span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Equal(t, parent.SpanContext().TraceID(), span.SpanContext().TraceID())
+	}
+}
+
+func TestTracingRapidScanSpan(t *testing.T) {
+	client, recorder, server := newTracedClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderNameContentType, "application/json")
+		w.Write([]byte(`{"totalCount": 0, "items": []}`))
+	}))
+
+	err, _ := client.PollRapidScanResults(server.URL+"/api/developer-scans/1", time.Millisecond, time.Second, 100, RapidScanOpts{})
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.NotEmpty(t, spans)
+	assert.Equal(t, "PollRapidScanResults", spans[len(spans)-1].Name())
+	assert.Equal(t, "GET /api/developer-scans/{id}/full-result", spans[0].Name())
+}
+
+func TestRouteTemplate(t *testing.T) {
+	assert.Equal(t, "/api/projects/{id}/versions/{id}/components", routeTemplate("/api/projects/0c8c9d73-6f9b-4e67-a3f4-0c2e7c6c1a11/versions/5d5b1c3e-0b8b-4c2c-9a4f-3b6c1f1f2e22/components"))
+	assert.Equal(t, "/api/vulnerabilities/{id}", routeTemplate("/api/vulnerabilities/CVE-2021-44228"))
+	assert.Equal(t, "/api/current-version", routeTemplate("/api/current-version"))
+}
+
+func TestTracingDisabledByDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Traceparent"))
+		w.Write([]byte(`{"version": "2024.1.0"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewWithClient(server.URL, 0, server.Client())
+	require.NoError(t, err)
+
+	_, err = client.CurrentVersion()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,153 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/pkg/errors"
+)
+
+// UpdateProject applies the changes of update to the project at link: the project is fetched, the changes are
+// merged onto it, so that the fields not changed keep their values, and it is put back. The updated project
+// is returned. If update.UpdatedAt is set and the project was updated since, nothing is changed and the
+// error matches ErrConflict.
+//
+// The project is put back with an If-Unmodified-Since precondition on the updatedAt it was fetched with, so
+// that a change made by someone else between the two requests is reported as a conflict too rather than
+// overwritten. The precondition only has the precision of a second, and a Black Duck server which does not
+// enforce it overwrites such a change all the same.
+func (c *Client) UpdateProject(link hubapi.ResourceLink, update *hubapi.ProjectUpdate) (*hubapi.Project, error) {
+	return c.UpdateProjectContext(context.Background(), link, update)
+}
+
+func (c *Client) UpdateProjectContext(ctx context.Context, link hubapi.ResourceLink, update *hubapi.ProjectUpdate) (*hubapi.Project, error) {
+	project, err := c.GetProjectContext(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+
+	if modifiedSince(project.UpdatedAt, update.UpdatedAt) {
+		return nil, HubClientStatusCodeErrorf(http.StatusConflict, "project %s was updated at %s, after %s", link.Href, project.UpdatedAt, update.UpdatedAt)
+	}
+
+	setIfChanged(&project.Name, update.Name)
+	setIfChanged(&project.Description, update.Description)
+	setIfChanged(&project.ProjectTier, update.ProjectTier)
+	setIfChanged(&project.ProjectOwner, update.ProjectOwner)
+	setIfChanged(&project.ProjectLevelAdjustments, update.ProjectLevelAdjustments)
+
+	if err := c.putUnmodified(ctx, link.Href, project, project.GetMimeType(), project.UpdatedAt); err != nil {
+		return nil, AnnotateHubClientErrorf(err, "unable to update project %s", link.Href)
+	}
+
+	if c.dryRun != nil {
+		return project, nil
+	}
+
+	return c.GetProjectContext(ctx, link)
+}
+
+// UpdateProjectVersion applies the changes of update to the project version at link like UpdateProject, with
+// settingUpdatedAt in place of updatedAt. The phase and distribution must be one of hubapi.ProjectVersionPhases
+// and hubapi.ProjectVersionDistributions.
+func (c *Client) UpdateProjectVersion(link hubapi.ResourceLink, update *hubapi.ProjectVersionUpdate) (*hubapi.ProjectVersion, error) {
+	return c.UpdateProjectVersionContext(context.Background(), link, update)
+}
+
+func (c *Client) UpdateProjectVersionContext(ctx context.Context, link hubapi.ResourceLink, update *hubapi.ProjectVersionUpdate) (*hubapi.ProjectVersion, error) {
+	if err := validateValue("phase", update.Phase, hubapi.ProjectVersionPhases); err != nil {
+		return nil, err
+	}
+	if err := validateValue("distribution", update.Distribution, hubapi.ProjectVersionDistributions); err != nil {
+		return nil, err
+	}
+
+	version, err := c.GetProjectVersionContext(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+
+	if modifiedSince(version.SettingUpdatedAt, update.SettingUpdatedAt) {
+		return nil, HubClientStatusCodeErrorf(http.StatusConflict, "project version %s was updated at %s, after %s", link.Href, version.SettingUpdatedAt, update.SettingUpdatedAt)
+	}
+
+	setIfChanged(&version.VersionName, update.VersionName)
+	setIfChanged(&version.Nickname, update.Nickname)
+	setIfChanged(&version.ReleaseComments, update.ReleaseComments)
+	setIfChanged(&version.Phase, update.Phase)
+	setIfChanged(&version.Distribution, update.Distribution)
+	if update.ReleasedOn != nil {
+		version.ReleasedOn = update.ReleasedOn
+	}
+
+	if err := c.putUnmodified(ctx, link.Href, version, version.GetMimeType(), version.SettingUpdatedAt); err != nil {
+		return nil, AnnotateHubClientErrorf(err, "unable to update project version %s", link.Href)
+	}
+
+	if c.dryRun != nil {
+		return version, nil
+	}
+
+	return c.GetProjectVersionContext(ctx, link)
+}
+
+// putUnmodified puts resource back to url unless it was modified after updatedAt, in which case the error
+// matches ErrConflict. Without updatedAt it is put back unconditionally.
+func (c *Client) putUnmodified(ctx context.Context, url string, resource interface{}, contentType string, updatedAt *time.Time) error {
+	var header http.Header
+	if updatedAt != nil {
+		header = http.Header{HeaderNameIfUnmodifiedSince: {updatedAt.UTC().Format(http.TimeFormat)}}
+	}
+
+	err := c.putJSON(ctx, url, resource, contentType, header, http.StatusOK, http.StatusNoContent)
+
+	var hce *HubClientError
+	if errors.As(err, &hce) && hce.StatusCode == http.StatusPreconditionFailed {
+		return HubClientStatusCodeErrorf(http.StatusConflict, "%s was updated after %s", url, updatedAt)
+	}
+
+	return err
+}
+
+func setIfChanged[T any](field *T, change *T) {
+	if change != nil {
+		*field = *change
+	}
+}
+
+// modifiedSince reports whether a resource last updated at updatedAt was updated after known. It cannot tell
+// if either is missing.
+func modifiedSince(updatedAt, known *time.Time) bool {
+	return updatedAt != nil && known != nil && !updatedAt.Equal(*known)
+}
+
+func validateValue(name string, value *string, valid []string) error {
+	if value == nil {
+		return nil
+	}
+
+	for _, v := range valid {
+		if *value == v {
+			return nil
+		}
+	}
+
+	return HubClientErrorf("invalid %s %q, expected one of %s", name, *value, strings.Join(valid, ", "))
+}
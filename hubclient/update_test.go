@@ -0,0 +1,179 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubclient
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resourceServer serves a single resource, which PUT replaces unless it was updated after If-Unmodified-Since
+type resourceServer struct {
+	lock     sync.Mutex
+	resource map[string]interface{}
+	puts     []string
+	// beforePut is called ahead of every PUT, to update the resource concurrently
+	beforePut func(resource map[string]interface{})
+}
+
+func newResourceServer(t *testing.T, resource string) (*resourceServer, *Client, hubapi.ResourceLink) {
+	rs := &resourceServer{}
+	require.NoError(t, json.Unmarshal([]byte(resource), &rs.resource))
+
+	server := httptest.NewServer(rs)
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL, WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+
+	return rs, client, hubapi.ResourceLink{Href: server.URL + "/api/projects/1"}
+}
+
+func (s *resourceServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if r.Method == http.MethodPut {
+		if s.beforePut != nil {
+			s.beforePut(s.resource)
+		}
+		if !s.unmodifiedSince(r.Header.Get(HeaderNameIfUnmodifiedSince)) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+
+		s.puts = append(s.puts, r.Header.Get(HeaderNameContentType))
+		s.resource = nil
+		json.NewDecoder(r.Body).Decode(&s.resource)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	json.NewEncoder(w).Encode(s.resource)
+}
+
+func (s *resourceServer) unmodifiedSince(since string) bool {
+	if since == "" {
+		return true
+	}
+
+	sinceTime, err := http.ParseTime(since)
+	if err != nil {
+		return true
+	}
+
+	for _, name := range []string{"updatedAt", "settingUpdatedAt"} {
+		if updatedAt, ok := s.resource[name].(string); ok {
+			t, _ := time.Parse(time.RFC3339, updatedAt)
+			return !t.Truncate(time.Second).After(sinceTime)
+		}
+	}
+
+	return true
+}
+
+func TestUpdateProjectKeepsOtherFields(t *testing.T) {
+	rs, client, link := newResourceServer(t, `{
+		"name": "demo", "description": "the demo", "projectTier": 3, "projectOwner": "https://blackduck/api/users/1",
+		"updatedAt": "2024-01-02T03:04:05.000Z", "customSignatureEnabled": true}`)
+
+	name, tier := "renamed", uint32(1)
+	project, err := client.UpdateProject(link, &hubapi.ProjectUpdate{Name: &name, ProjectTier: &tier})
+	require.NoError(t, err)
+
+	assert.Equal(t, "renamed", project.Name)
+	assert.Equal(t, uint32(1), project.ProjectTier)
+	assert.Equal(t, "the demo", project.Description)
+	assert.Equal(t, "https://blackduck/api/users/1", project.ProjectOwner)
+	assert.Equal(t, []string{hubapi.ContentTypeBdProjectDetailV4}, rs.puts)
+	assert.Equal(t, true, rs.resource["customSignatureEnabled"], "fields unknown to hubapi.Project are put back")
+}
+
+func TestUpdateProjectDetectsConcurrentModification(t *testing.T) {
+	rs, client, link := newResourceServer(t, `{"name": "demo", "updatedAt": "2024-01-02T03:04:05.000Z"}`)
+
+	known := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	name := "renamed"
+	_, err := client.UpdateProject(link, &hubapi.ProjectUpdate{Name: &name, UpdatedAt: &known})
+	assert.True(t, errors.Is(err, ErrConflict))
+	assert.Empty(t, rs.puts)
+
+	known = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	_, err = client.UpdateProject(link, &hubapi.ProjectUpdate{Name: &name, UpdatedAt: &known})
+	require.NoError(t, err)
+	assert.Len(t, rs.puts, 1)
+}
+
+func TestUpdateProjectDetectsModificationBeforeThePut(t *testing.T) {
+	rs, client, link := newResourceServer(t, `{"name": "demo", "updatedAt": "2024-01-02T03:04:05.000Z"}`)
+	rs.beforePut = func(resource map[string]interface{}) {
+		resource["name"] = "taken over"
+		resource["updatedAt"] = "2024-01-02T03:04:06.000Z"
+	}
+
+	name := "renamed"
+	_, err := client.UpdateProject(link, &hubapi.ProjectUpdate{Name: &name})
+	assert.True(t, errors.Is(err, ErrConflict), "the change of the other client is not overwritten, got %v", err)
+	assert.Empty(t, rs.puts)
+	assert.Equal(t, "taken over", rs.resource["name"])
+}
+
+func TestUpdateProjectVersion(t *testing.T) {
+	rs, client, link := newResourceServer(t, `{
+		"versionName": "1.0", "nickname": "first", "phase": "DEVELOPMENT", "distribution": "EXTERNAL",
+		"settingUpdatedAt": "2024-01-02T03:04:05.000Z"}`)
+
+	released := hubapi.ProjectVersionPhaseReleased
+	releasedOn := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	version, err := client.UpdateProjectVersion(link, &hubapi.ProjectVersionUpdate{Phase: &released, ReleasedOn: &releasedOn})
+	require.NoError(t, err)
+
+	assert.Equal(t, hubapi.ProjectVersionPhaseReleased, version.Phase)
+	assert.True(t, releasedOn.Equal(*version.ReleasedOn))
+	assert.Equal(t, "first", version.Nickname)
+	assert.Equal(t, hubapi.ProjectVersionDistributionExternal, version.Distribution)
+	assert.Equal(t, []string{hubapi.ContentTypeBdProjectDetailV5}, rs.puts)
+
+	known := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err = client.UpdateProjectVersion(link, &hubapi.ProjectVersionUpdate{Phase: &released, SettingUpdatedAt: &known})
+	assert.True(t, errors.Is(err, ErrConflict))
+
+	rs.beforePut = func(resource map[string]interface{}) {
+		resource["settingUpdatedAt"] = "2024-03-01T00:00:00.000Z"
+	}
+	_, err = client.UpdateProjectVersion(link, &hubapi.ProjectVersionUpdate{Nickname: &released})
+	assert.True(t, errors.Is(err, ErrConflict))
+	assert.Len(t, rs.puts, 1)
+}
+
+func TestUpdateProjectVersionValidates(t *testing.T) {
+	rs, client, link := newResourceServer(t, `{"versionName": "1.0"}`)
+
+	phase, distribution := "SHIPPED", "PUBLIC"
+	_, err := client.UpdateProjectVersion(link, &hubapi.ProjectVersionUpdate{Phase: &phase})
+	assert.EqualError(t, err, "invalid phase \"SHIPPED\", expected one of PLANNING, DEVELOPMENT, RELEASED, DEPRECATED, ARCHIVED")
+	_, err = client.UpdateProjectVersion(link, &hubapi.ProjectVersionUpdate{Distribution: &distribution})
+	assert.Error(t, err)
+	assert.Empty(t, rs.puts)
+}
@@ -15,15 +15,20 @@
 package hubclient
 
 import (
+	"context"
 	"github.com/blackducksoftware/hub-client-go/hubapi"
 )
 
 // TODO: This API should also be returning a location
 func (c *Client) CreateUser(userRequest *hubapi.UserRequest) (*hubapi.User, error) {
+	return c.CreateUserContext(context.Background(), userRequest)
+}
+
+func (c *Client) CreateUserContext(ctx context.Context, userRequest *hubapi.UserRequest) (*hubapi.User, error) {
 	usersURL := hubapi.BuildUrl(c.baseURL, hubapi.UsersApi)
 
 	var result hubapi.User
-	_, err := c.HttpPostJSONExpectResult(usersURL, userRequest, &result, "application/json", 201)
+	_, err := c.HttpPostJSONExpectResultContext(ctx, usersURL, userRequest, &result, "application/json", 201)
 
 	if err != nil {
 		return nil, TraceHubClientError(err)
@@ -38,10 +43,14 @@ func (c *Client) CreateUser(userRequest *hubapi.UserRequest) (*hubapi.User, erro
 }
 
 func (c *Client) ListUsers(options *hubapi.GetListOptions) (*hubapi.UserList, error) {
+	return c.ListUsersContext(context.Background(), options)
+}
+
+func (c *Client) ListUsersContext(ctx context.Context, options *hubapi.GetListOptions) (*hubapi.UserList, error) {
 	usersURL := hubapi.BuildUrl(c.baseURL, hubapi.UsersApi)
 
 	var userList hubapi.UserList
-	err := c.GetPage(usersURL, options, &userList)
+	err := c.GetPageContext(ctx, usersURL, options, &userList)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve user list")
@@ -51,8 +60,12 @@ func (c *Client) ListUsers(options *hubapi.GetListOptions) (*hubapi.UserList, er
 }
 
 func (c *Client) GetUser(link hubapi.ResourceLink) (*hubapi.User, error) {
+	return c.GetUserContext(context.Background(), link)
+}
+
+func (c *Client) GetUserContext(ctx context.Context, link hubapi.ResourceLink) (*hubapi.User, error) {
 	var user hubapi.User
-	err := c.HttpGetJSON(link.Href, &user, 200)
+	err := c.HttpGetJSONContext(ctx, link.Href, &user, 200)
 
 	if err != nil {
 		return nil, AnnotateHubClientError(err, "Error trying to retrieve a user")
@@ -15,17 +15,21 @@
 package hubclient
 
 import (
+	"context"
 	"github.com/blackducksoftware/hub-client-go/hubapi"
-	log "github.com/sirupsen/logrus"
 )
 
 func (c *Client) GetVulnerability(link hubapi.ResourceLink) (*hubapi.Vulnerability, error) {
+	return c.GetVulnerabilityContext(context.Background(), link)
+}
+
+func (c *Client) GetVulnerabilityContext(ctx context.Context, link hubapi.ResourceLink) (*hubapi.Vulnerability, error) {
 	var vulnerability hubapi.Vulnerability
-	err := c.HttpGetJSON(link.Href, &vulnerability, 200)
+	err := c.HttpGetJSONContext(ctx, link.Href, &vulnerability, 200)
 
 	if err != nil {
-		log.Errorf("Error trying to retrieve a component: %+v.", err)
-		return nil, err
+		c.log().Error("Error trying to retrieve a vulnerability", urlField(link.Href), errorField(err))
+		return nil, AnnotateHubClientError(err, "Error trying to retrieve a vulnerability")
 	}
 
 	return &vulnerability, nil
@@ -0,0 +1,159 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubtest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/pkg/errors"
+)
+
+type codeLocation struct {
+	id       string
+	href     string
+	location hubapi.CodeLocation
+	scans    []hubapi.ScanSummary
+}
+
+// AddCodeLocation creates a code location with one completed scan. It is mapped to the project version
+// whose URL is in location.MappedProjectVersion, if set. It returns the URL of the code location.
+func (s *Server) AddCodeLocation(location hubapi.CodeLocation) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if location.MappedProjectVersion != "" && s.projectVersionByHref(location.MappedProjectVersion) == nil {
+		return "", errors.Errorf("no project version at %s", location.MappedProjectVersion)
+	}
+
+	id := s.newID()
+	cl := &codeLocation{
+		id:       id,
+		href:     s.href(hubapi.CodeLocationsApi + "/" + id),
+		location: location,
+	}
+
+	if cl.location.CreatedAt == nil {
+		cl.location.CreatedAt = now()
+	}
+	if cl.location.UpdatedAt == nil {
+		cl.location.UpdatedAt = cl.location.CreatedAt
+	}
+
+	cl.scans = []hubapi.ScanSummary{{
+		Status:    "COMPLETE",
+		CreatedAt: cl.location.CreatedAt,
+		UpdatedAt: cl.location.UpdatedAt,
+		Meta: meta(s.href("/api/scan-summaries/"+s.newID()), []string{http.MethodGet},
+			link(hubapi.ScanSummaryRelCodeLocation, cl.href),
+		),
+	}}
+	s.codeLocations = append(s.codeLocations, cl)
+
+	return cl.href, nil
+}
+
+// unmapCodeLocations detaches the code locations from a project version being deleted
+func (s *Server) unmapCodeLocations(versionHref string) {
+	for _, cl := range s.codeLocations {
+		if cl.location.MappedProjectVersion == versionHref {
+			cl.location.MappedProjectVersion = ""
+		}
+	}
+}
+
+func (s *Server) renderCodeLocation(cl *codeLocation) hubapi.CodeLocation {
+	rendered := cl.location
+	rendered.Meta = meta(cl.href, []string{http.MethodGet, http.MethodDelete},
+		link(hubapi.CodeLocationRelScans, cl.href+"/scan-summaries"),
+	)
+
+	return rendered
+}
+
+func (s *Server) writeCodeLocations(c *call, versionHref string) {
+	q, ok := s.parseListQuery(c, "name")
+	if !ok {
+		return
+	}
+
+	var locations []hubapi.CodeLocation
+	for _, cl := range s.codeLocations {
+		if versionHref == "" || cl.location.MappedProjectVersion == versionHref {
+			locations = append(locations, s.renderCodeLocation(cl))
+		}
+	}
+
+	writeList(s, c, q, locations, func(cl hubapi.CodeLocation) string { return cl.Name })
+}
+
+func (s *Server) listCodeLocations(c *call) {
+	s.writeCodeLocations(c, "")
+}
+
+func (s *Server) listProjectVersionCodeLocations(c *call) {
+	if v := s.projectVersion(c); v != nil {
+		s.writeCodeLocations(c, v.href)
+	}
+}
+
+// codeLocation returns the code location addressed by the call, or writes a 404 and returns nil
+func (s *Server) codeLocation(c *call) *codeLocation {
+	for _, cl := range s.codeLocations {
+		if cl.id == c.ids[0] {
+			return cl
+		}
+	}
+
+	s.writeError(c.w, http.StatusNotFound, "{central.code_location_not_found}", fmt.Sprintf("no code location with id %s", c.ids[0]))
+	return nil
+}
+
+func (s *Server) getCodeLocation(c *call) {
+	if cl := s.codeLocation(c); cl != nil {
+		s.writeJSON(c.w, http.StatusOK, c.mediaType, s.renderCodeLocation(cl))
+	}
+}
+
+func (s *Server) deleteCodeLocation(c *call) {
+	cl := s.codeLocation(c)
+	if cl == nil {
+		return
+	}
+
+	for i := range s.codeLocations {
+		if s.codeLocations[i] == cl {
+			s.codeLocations = append(s.codeLocations[:i], s.codeLocations[i+1:]...)
+			break
+		}
+	}
+
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listScanSummaries(c *call) {
+	cl := s.codeLocation(c)
+	if cl == nil {
+		return
+	}
+
+	q, ok := s.parseListQuery(c, "status")
+	if !ok {
+		return
+	}
+
+	writeList(s, c, q, cl.scans, func(scan hubapi.ScanSummary) string { return scan.Status })
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubtest
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Fault makes the Server misbehave on matching requests, see Server.InjectFault
+type Fault struct {
+	// Method is the request method the fault applies to, any method if empty
+	Method string
+	// PathPrefix is the start of the URL paths the fault applies to, any path if empty
+	PathPrefix string
+	// Times is how many matching requests are affected, all of them if zero
+	Times int
+
+	// Delay holds up the response
+	Delay time.Duration
+	// StatusCode is the status of the error response sent instead of serving the request.
+	// If zero the request is served normally after the delay.
+	StatusCode int
+	// ErrorCode is the Black Duck error code of the error response
+	ErrorCode string
+	// Header is added to the error response, for example a Retry-After header
+	Header http.Header
+	// Drop closes the connection without sending a response
+	Drop bool
+	// Before is called ahead of the request, before any delay, for example to change the data of the Server
+	// like another client racing with the request would. The Server may be used from within it.
+	Before func()
+
+	hits int
+}
+
+// InjectFault adds a fault to the Server. A request is affected by the first fault matching it which has
+// not been used up yet. Faults apply to every request, including authentication.
+func (s *Server) InjectFault(fault Fault) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes all faults from the Server
+func (s *Server) ClearFaults() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.faults = nil
+}
+
+// matchFault returns the fault applying to the request, counting it as used
+func (s *Server) matchFault(r *http.Request) *Fault {
+	for _, f := range s.faults {
+		if f.Times > 0 && f.hits >= f.Times {
+			continue
+		}
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, f.PathPrefix) {
+			continue
+		}
+
+		f.hits++
+		return f
+	}
+
+	return nil
+}
+
+// injectFault makes the request fail as described by the fault. It reports whether the response is done.
+func (s *Server) injectFault(w http.ResponseWriter, r *http.Request, f *Fault) bool {
+	if f.Delay > 0 {
+		timer := time.NewTimer(f.Delay)
+		defer timer.Stop()
+
+		select {
+		case <-r.Context().Done():
+			return true
+		case <-timer.C:
+		}
+	}
+
+	if f.Drop {
+		if hijacker, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hijacker.Hijack(); err == nil {
+				conn.Close()
+				return true
+			}
+		}
+		panic(http.ErrAbortHandler)
+	}
+
+	if f.StatusCode == 0 {
+		return false
+	}
+
+	for key, values := range f.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+
+	errorCode := f.ErrorCode
+	if errorCode == "" {
+		errorCode = "{hubtest.injected_fault}"
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.writeError(w, f.StatusCode, errorCode, http.StatusText(f.StatusCode))
+
+	return true
+}
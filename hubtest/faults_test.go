@@ -0,0 +1,106 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/blackducksoftware/hub-client-go/hubclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultIsRetried(t *testing.T) {
+	policy := hubclient.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	server, client := newTestClient(t, hubclient.WithRetryPolicy(policy))
+
+	server.InjectFault(Fault{
+		Method:     http.MethodGet,
+		PathPrefix: hubapi.ProjectsApi,
+		Times:      2,
+		StatusCode: http.StatusServiceUnavailable,
+		Header:     http.Header{"Retry-After": []string{"0"}},
+	})
+
+	list, err := client.ListProjects(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, list.TotalCount)
+	assert.Len(t, server.Requests(), 4)
+}
+
+func TestFaultErrorResponse(t *testing.T) {
+	server, client := newTestClient(t)
+
+	server.InjectFault(Fault{PathPrefix: hubapi.PolicyRulesApi, StatusCode: http.StatusInternalServerError, ErrorCode: "{central.internal_error}"})
+
+	_, err := client.ListPolicyRules(nil)
+	var hce *hubclient.HubClientError
+	require.True(t, errors.As(err, &hce))
+	assert.Equal(t, http.StatusInternalServerError, hce.StatusCode)
+	assert.Equal(t, "{central.internal_error}", hce.HubError.ErrorCode)
+	assert.NotEmpty(t, hce.HubError.LogRef)
+
+	// other paths are not affected, and the fault is gone once cleared
+	_, err = client.ListProjects(nil)
+	require.NoError(t, err)
+	server.ClearFaults()
+	_, err = client.ListPolicyRules(nil)
+	require.NoError(t, err)
+}
+
+func TestFaultDropAndDelay(t *testing.T) {
+	server, client := newTestClient(t)
+
+	// the transport resends an idempotent request once when a reused connection is dropped
+	server.InjectFault(Fault{PathPrefix: hubapi.UsersApi, Times: 2, Drop: true})
+	_, err := client.ListUsers(nil)
+	require.Error(t, err)
+	assert.Equal(t, 0, statusCode(err))
+	server.ClearFaults()
+
+	server.InjectFault(Fault{PathPrefix: hubapi.UsersApi, Delay: time.Second})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.ListUsersContext(ctx, nil)
+	require.Error(t, err)
+	assert.True(t, errors.Is(ctx.Err(), context.DeadlineExceeded))
+}
+
+func TestFaultBefore(t *testing.T) {
+	server, client := newTestClient(t)
+
+	server.InjectFault(Fault{
+		Method:     http.MethodPost,
+		PathPrefix: hubapi.ProjectsApi,
+		Times:      1,
+		Before: func() {
+			_, err := server.AddProject(hubapi.ProjectRequest{Name: "demo"})
+			assert.NoError(t, err)
+		},
+	})
+
+	_, err := client.CreateProject(&hubapi.ProjectRequest{Name: "demo"})
+	assert.Equal(t, http.StatusConflict, statusCode(err), "the project was created by the other client first")
+
+	list, err := client.ListProjects(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, list.TotalCount)
+}
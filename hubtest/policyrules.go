@@ -0,0 +1,177 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubtest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/pkg/errors"
+)
+
+type policyRule struct {
+	id   string
+	href string
+	rule hubapi.PolicyRule
+}
+
+// AddPolicyRule creates a policy rule as the system administrator. It returns the URL of the rule.
+func (s *Server) AddPolicyRule(request hubapi.PolicyRuleRequest) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if status, code, message := s.validatePolicyRule(request, nil); status != 0 {
+		return "", errors.Errorf("%d %s: %s", status, code, message)
+	}
+
+	return s.addPolicyRule(request, s.users[0]).href, nil
+}
+
+func (s *Server) validatePolicyRule(request hubapi.PolicyRuleRequest, updated *policyRule) (int, string, string) {
+	if strings.TrimSpace(request.Name) == "" {
+		return http.StatusBadRequest, "{central.constraint_violation.policy_rule_name_required}", "the policy rule name is required"
+	}
+
+	for _, pr := range s.policyRules {
+		if pr != updated && strings.EqualFold(pr.rule.Name, request.Name) {
+			return http.StatusConflict, "{central.constraint_violation.policy_rule_name_duplicate_not_allowed}",
+				fmt.Sprintf("a policy rule named %s already exists", request.Name)
+		}
+	}
+
+	return 0, "", ""
+}
+
+func (s *Server) addPolicyRule(request hubapi.PolicyRuleRequest, creator *user) *policyRule {
+	id := s.newID()
+	pr := &policyRule{
+		id:   id,
+		href: s.href(hubapi.PolicyRulesApi + "/" + id),
+	}
+
+	pr.rule.CreatedAt = now()
+	pr.rule.CreatedBy = creator.user.UserName
+	pr.rule.CreatedByUser = creator.href
+	applyPolicyRuleRequest(&pr.rule, request)
+	pr.rule.UpdatedAt = pr.rule.CreatedAt
+	pr.rule.UpdatedBy = pr.rule.CreatedBy
+	pr.rule.UpdatedByUser = pr.rule.CreatedByUser
+	s.policyRules = append(s.policyRules, pr)
+
+	return pr
+}
+
+func applyPolicyRuleRequest(rule *hubapi.PolicyRule, request hubapi.PolicyRuleRequest) {
+	rule.Name = request.Name
+	rule.Description = request.Description
+	rule.Enabled = request.Enabled
+	rule.Overridable = request.Overridable
+	rule.Severity = request.Severity
+	rule.Expression = request.Expression
+}
+
+func (s *Server) renderPolicyRule(pr *policyRule) hubapi.PolicyRule {
+	rendered := pr.rule
+	rendered.Meta = meta(pr.href, []string{http.MethodGet, http.MethodPut, http.MethodDelete})
+
+	return rendered
+}
+
+func (s *Server) listPolicyRules(c *call) {
+	q, ok := s.parseListQuery(c, "name")
+	if !ok {
+		return
+	}
+
+	var rules []hubapi.PolicyRule
+	for _, pr := range s.policyRules {
+		rules = append(rules, s.renderPolicyRule(pr))
+	}
+
+	writeList(s, c, q, rules, func(rule hubapi.PolicyRule) string { return rule.Name })
+}
+
+func (s *Server) createPolicyRule(c *call) {
+	var request hubapi.PolicyRuleRequest
+	if !s.decode(c, &request) {
+		return
+	}
+
+	if status, code, message := s.validatePolicyRule(request, nil); status != 0 {
+		s.writeError(c.w, status, code, message)
+		return
+	}
+
+	s.writeCreated(c.w, s.addPolicyRule(request, c.user).href)
+}
+
+// policyRule returns the policy rule addressed by the call, or writes a 404 and returns nil
+func (s *Server) policyRule(c *call) *policyRule {
+	for _, pr := range s.policyRules {
+		if pr.id == c.ids[0] {
+			return pr
+		}
+	}
+
+	s.writeError(c.w, http.StatusNotFound, "{central.policy_rule_not_found}", fmt.Sprintf("no policy rule with id %s", c.ids[0]))
+	return nil
+}
+
+func (s *Server) getPolicyRule(c *call) {
+	if pr := s.policyRule(c); pr != nil {
+		s.writeJSON(c.w, http.StatusOK, c.mediaType, s.renderPolicyRule(pr))
+	}
+}
+
+func (s *Server) updatePolicyRule(c *call) {
+	pr := s.policyRule(c)
+	if pr == nil {
+		return
+	}
+
+	var request hubapi.PolicyRuleRequest
+	if !s.decode(c, &request) {
+		return
+	}
+
+	if status, code, message := s.validatePolicyRule(request, pr); status != 0 {
+		s.writeError(c.w, status, code, message)
+		return
+	}
+
+	applyPolicyRuleRequest(&pr.rule, request)
+	pr.rule.UpdatedAt = now()
+	pr.rule.UpdatedBy = c.user.user.UserName
+	pr.rule.UpdatedByUser = c.user.href
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deletePolicyRule(c *call) {
+	pr := s.policyRule(c)
+	if pr == nil {
+		return
+	}
+
+	for i := range s.policyRules {
+		if s.policyRules[i] == pr {
+			s.policyRules = append(s.policyRules[:i], s.policyRules[i+1:]...)
+			break
+		}
+	}
+
+	c.w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,543 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubtest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/pkg/errors"
+)
+
+type project struct {
+	id       string
+	href     string
+	project  hubapi.Project
+	versions []*projectVersion
+	users    []*user
+}
+
+type projectVersion struct {
+	id         string
+	href       string
+	project    *project
+	version    hubapi.ProjectVersion
+	components []*bomComponent
+}
+
+type bomComponent struct {
+	id        string
+	href      string
+	component hubapi.BomComponent
+}
+
+var projectVersionPhases = []string{
+	hubapi.ProjectVersionPhasePlanning,
+	hubapi.ProjectVersionPhaseDevelopment,
+	hubapi.ProjectVersionPhaseReleased,
+	hubapi.ProjectVersionPhaseDeprecated,
+	hubapi.ProjectVersionPhaseArchived,
+}
+
+var projectVersionDistributions = []string{
+	hubapi.ProjectVersionDistributionExternal,
+	hubapi.ProjectVersionDistributionSaaS,
+	hubapi.ProjectVersionDistributionInternal,
+	hubapi.ProjectVersionDistributionOpenSource,
+}
+
+// AddProject creates a project, and its first version if the request has one, as the system administrator.
+// It returns the URL of the project.
+func (s *Server) AddProject(request hubapi.ProjectRequest) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if status, code, message := s.validateProject(request, nil); status != 0 {
+		return "", errors.Errorf("%d %s: %s", status, code, message)
+	}
+
+	return s.addProject(request, s.users[0]).href, nil
+}
+
+// AddProjectVersion creates a version of the project at projectURL as the system administrator.
+// It returns the URL of the version.
+func (s *Server) AddProjectVersion(projectURL string, request hubapi.ProjectVersionRequest) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	p := s.projectByHref(projectURL)
+	if p == nil {
+		return "", errors.Errorf("no project at %s", projectURL)
+	}
+
+	if status, code, message := s.validateProjectVersion(p, request, nil); status != 0 {
+		return "", errors.Errorf("%d %s: %s", status, code, message)
+	}
+
+	return s.addProjectVersion(p, request, s.users[0]).href, nil
+}
+
+// AddBomComponent adds a component to the BOM of the project version at versionURL. The _meta of the
+// component is filled in by the server. It returns the URL of the BOM component.
+func (s *Server) AddBomComponent(versionURL string, component hubapi.BomComponent) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	v := s.projectVersionByHref(versionURL)
+	if v == nil {
+		return "", errors.Errorf("no project version at %s", versionURL)
+	}
+
+	id := s.newID()
+	bc := &bomComponent{id: id, href: v.href + "/components/" + id, component: component}
+	v.components = append(v.components, bc)
+
+	return bc.href, nil
+}
+
+func (s *Server) projectByHref(href string) *project {
+	for _, p := range s.projects {
+		if p.href == href {
+			return p
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) projectVersionByHref(href string) *projectVersion {
+	for _, p := range s.projects {
+		for _, v := range p.versions {
+			if v.href == href {
+				return v
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateProject checks a request to create or update a project. It returns the status code, error code and
+// message of the error response, or a zero status code if the request is valid.
+func (s *Server) validateProject(request hubapi.ProjectRequest, updated *project) (int, string, string) {
+	if strings.TrimSpace(request.Name) == "" {
+		return http.StatusBadRequest, "{central.constraint_violation.project_name_required}", "the project name is required"
+	}
+
+	for _, p := range s.projects {
+		if p != updated && strings.EqualFold(p.project.Name, request.Name) {
+			return http.StatusConflict, "{central.constraint_violation.project_name_duplicate_not_allowed}",
+				fmt.Sprintf("a project named %s already exists", request.Name)
+		}
+	}
+
+	if request.VersionRequest != nil && updated == nil {
+		return s.validateProjectVersion(nil, *request.VersionRequest, nil)
+	}
+
+	return 0, "", ""
+}
+
+func (s *Server) validateProjectVersion(p *project, request hubapi.ProjectVersionRequest, updated *projectVersion) (int, string, string) {
+	if strings.TrimSpace(request.VersionName) == "" {
+		return http.StatusBadRequest, "{central.constraint_violation.project_version_name_required}", "the version name is required"
+	}
+
+	if !contains(projectVersionPhases, request.Phase) {
+		return http.StatusBadRequest, "{central.constraint_violation.project_version_phase_invalid}",
+			fmt.Sprintf("invalid phase %q, expected one of %s", request.Phase, strings.Join(projectVersionPhases, ", "))
+	}
+
+	if !contains(projectVersionDistributions, request.Distribution) {
+		return http.StatusBadRequest, "{central.constraint_violation.project_version_distribution_invalid}",
+			fmt.Sprintf("invalid distribution %q, expected one of %s", request.Distribution, strings.Join(projectVersionDistributions, ", "))
+	}
+
+	if p != nil {
+		for _, v := range p.versions {
+			if v != updated && v.version.VersionName == request.VersionName {
+				return http.StatusConflict, "{central.constraint_violation.project_version_name_duplicate_not_allowed}",
+					fmt.Sprintf("the project %s already has a version named %s", p.project.Name, request.VersionName)
+			}
+		}
+	}
+
+	return 0, "", ""
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) addProject(request hubapi.ProjectRequest, creator *user) *project {
+	id := s.newID()
+	p := &project{
+		id:   id,
+		href: s.href(hubapi.ProjectsApi + "/" + id),
+	}
+
+	p.project.CreatedAt = now()
+	p.project.CreatedBy = creator.user.UserName
+	p.project.CreatedByUser = creator.href
+	p.project.Source = "CUSTOM"
+	applyProjectRequest(&p.project, request)
+	p.project.UpdatedAt = p.project.CreatedAt
+	s.projects = append(s.projects, p)
+
+	if request.VersionRequest != nil {
+		s.addProjectVersion(p, *request.VersionRequest, creator)
+	}
+
+	return p
+}
+
+func applyProjectRequest(p *hubapi.Project, request hubapi.ProjectRequest) {
+	p.Name = request.Name
+	p.Description = request.Description
+	p.ProjectLevelAdjustments = request.ProjectLevelAdjustments
+	if request.ProjectTier != nil {
+		p.ProjectTier = uint32(*request.ProjectTier)
+	}
+	if request.ProjectOwner != nil {
+		p.ProjectOwner = *request.ProjectOwner
+	}
+}
+
+func (s *Server) addProjectVersion(p *project, request hubapi.ProjectVersionRequest, creator *user) *projectVersion {
+	id := s.newID()
+	v := &projectVersion{
+		id:      id,
+		href:    p.href + "/versions/" + id,
+		project: p,
+	}
+
+	v.version.CreatedAt = now()
+	v.version.CreatedBy = creator.user.UserName
+	v.version.CreatedByUser = creator.href
+	v.version.Source = "CUSTOM"
+	applyProjectVersionRequest(&v.version, request)
+	v.version.SettingUpdatedAt = v.version.CreatedAt
+	p.versions = append(p.versions, v)
+
+	return v
+}
+
+func applyProjectVersionRequest(v *hubapi.ProjectVersion, request hubapi.ProjectVersionRequest) {
+	v.VersionName = request.VersionName
+	v.Nickname = request.Nickname
+	v.ReleaseComments = request.ReleaseComments
+	v.ReleasedOn = request.ReleasedOn
+	v.Phase = request.Phase
+	v.Distribution = request.Distribution
+	v.License = nil
+	if len(request.License) > 0 {
+		license := request.License[0]
+		v.License = &license
+	}
+}
+
+func (s *Server) renderProject(p *project) hubapi.Project {
+	rendered := p.project
+	rendered.Meta = meta(p.href, []string{http.MethodGet, http.MethodPut, http.MethodDelete},
+		link(hubapi.ProjectRelVersions, p.href+"/versions"),
+		link(hubapi.ProjectRelUsers, p.href+"/users"),
+	)
+
+	return rendered
+}
+
+func (s *Server) renderProjectVersion(v *projectVersion) hubapi.ProjectVersion {
+	rendered := v.version
+	rendered.Meta = meta(v.href, []string{http.MethodGet, http.MethodPut, http.MethodDelete},
+		link(hubapi.ProjectVersionRelProject, v.project.href),
+		link(hubapi.ProjectVersionRelComponents, v.href+"/components"),
+		link(hubapi.ProjectVersionRelCodeLocations, v.href+"/codelocations"),
+	)
+
+	return rendered
+}
+
+func (s *Server) renderBomComponent(bc *bomComponent) hubapi.BomComponent {
+	rendered := bc.component
+	rendered.Meta = meta(bc.href, []string{http.MethodGet})
+
+	return rendered
+}
+
+// project returns the project addressed by the call, or writes a 404 and returns nil
+func (s *Server) project(c *call) *project {
+	for _, p := range s.projects {
+		if p.id == c.ids[0] {
+			return p
+		}
+	}
+
+	s.writeError(c.w, http.StatusNotFound, "{central.project_not_found}", fmt.Sprintf("no project with id %s", c.ids[0]))
+	return nil
+}
+
+// projectVersion returns the project version addressed by the call, or writes a 404 and returns nil
+func (s *Server) projectVersion(c *call) *projectVersion {
+	p := s.project(c)
+	if p == nil {
+		return nil
+	}
+
+	for _, v := range p.versions {
+		if v.id == c.ids[1] {
+			return v
+		}
+	}
+
+	s.writeError(c.w, http.StatusNotFound, "{central.project_version_not_found}", fmt.Sprintf("no version with id %s in project %s", c.ids[1], p.project.Name))
+	return nil
+}
+
+func (s *Server) listProjects(c *call) {
+	q, ok := s.parseListQuery(c, "name")
+	if !ok {
+		return
+	}
+
+	var projects []hubapi.Project
+	for _, p := range s.projects {
+		projects = append(projects, s.renderProject(p))
+	}
+
+	writeList(s, c, q, projects, func(p hubapi.Project) string { return p.Name })
+}
+
+func (s *Server) createProject(c *call) {
+	var request hubapi.ProjectRequest
+	if !s.decode(c, &request) {
+		return
+	}
+
+	if status, code, message := s.validateProject(request, nil); status != 0 {
+		s.writeError(c.w, status, code, message)
+		return
+	}
+
+	s.writeCreated(c.w, s.addProject(request, c.user).href)
+}
+
+func (s *Server) getProject(c *call) {
+	if p := s.project(c); p != nil {
+		s.writeJSON(c.w, http.StatusOK, c.mediaType, s.renderProject(p))
+	}
+}
+
+func (s *Server) updateProject(c *call) {
+	p := s.project(c)
+	if p == nil {
+		return
+	}
+
+	var request hubapi.ProjectRequest
+	if !s.decode(c, &request) {
+		return
+	}
+
+	if status, code, message := s.validateProject(request, p); status != 0 {
+		s.writeError(c.w, status, code, message)
+		return
+	}
+
+	applyProjectRequest(&p.project, request)
+	p.project.UpdatedAt = now()
+	p.project.UpdatedBy = c.user.user.UserName
+	p.project.UpdatedByUser = c.user.href
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteProject(c *call) {
+	p := s.project(c)
+	if p == nil {
+		return
+	}
+
+	for i := range s.projects {
+		if s.projects[i] == p {
+			s.projects = append(s.projects[:i], s.projects[i+1:]...)
+			break
+		}
+	}
+
+	for _, v := range p.versions {
+		s.unmapCodeLocations(v.href)
+	}
+
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listProjectUsers(c *call) {
+	p := s.project(c)
+	if p == nil {
+		return
+	}
+
+	q, ok := s.parseListQuery(c, "userName")
+	if !ok {
+		return
+	}
+
+	var users []hubapi.User
+	for _, u := range p.users {
+		users = append(users, s.renderUser(u))
+	}
+
+	writeList(s, c, q, users, func(u hubapi.User) string { return u.UserName })
+}
+
+func (s *Server) assignProjectUser(c *call) {
+	p := s.project(c)
+	if p == nil {
+		return
+	}
+
+	var request hubapi.UserAssignmentRequest
+	if !s.decode(c, &request) {
+		return
+	}
+
+	u := s.userByHref(request.User)
+	if u == nil {
+		s.writeError(c.w, http.StatusBadRequest, "{central.user_not_found}", fmt.Sprintf("no user at %s", request.User))
+		return
+	}
+
+	for _, assigned := range p.users {
+		if assigned == u {
+			s.writeError(c.w, http.StatusConflict, "{central.constraint_violation.user_already_assigned}",
+				fmt.Sprintf("%s is already assigned to %s", u.user.UserName, p.project.Name))
+			return
+		}
+	}
+
+	p.users = append(p.users, u)
+	s.writeCreated(c.w, p.href+"/users/"+u.id)
+}
+
+func (s *Server) listProjectVersions(c *call) {
+	p := s.project(c)
+	if p == nil {
+		return
+	}
+
+	q, ok := s.parseListQuery(c, "versionName")
+	if !ok {
+		return
+	}
+
+	var versions []hubapi.ProjectVersion
+	for _, v := range p.versions {
+		versions = append(versions, s.renderProjectVersion(v))
+	}
+
+	writeList(s, c, q, versions, func(v hubapi.ProjectVersion) string { return v.VersionName })
+}
+
+func (s *Server) createProjectVersion(c *call) {
+	p := s.project(c)
+	if p == nil {
+		return
+	}
+
+	var request hubapi.ProjectVersionRequest
+	if !s.decode(c, &request) {
+		return
+	}
+
+	if status, code, message := s.validateProjectVersion(p, request, nil); status != 0 {
+		s.writeError(c.w, status, code, message)
+		return
+	}
+
+	s.writeCreated(c.w, s.addProjectVersion(p, request, c.user).href)
+}
+
+func (s *Server) getProjectVersion(c *call) {
+	if v := s.projectVersion(c); v != nil {
+		s.writeJSON(c.w, http.StatusOK, c.mediaType, s.renderProjectVersion(v))
+	}
+}
+
+func (s *Server) updateProjectVersion(c *call) {
+	v := s.projectVersion(c)
+	if v == nil {
+		return
+	}
+
+	var request hubapi.ProjectVersionRequest
+	if !s.decode(c, &request) {
+		return
+	}
+
+	if status, code, message := s.validateProjectVersion(v.project, request, v); status != 0 {
+		s.writeError(c.w, status, code, message)
+		return
+	}
+
+	applyProjectVersionRequest(&v.version, request)
+	v.version.SettingUpdatedAt = now()
+	v.version.SettingUpdatedBy = c.user.user.UserName
+	v.version.SettingUpdatedByUser = c.user.href
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteProjectVersion(c *call) {
+	v := s.projectVersion(c)
+	if v == nil {
+		return
+	}
+
+	p := v.project
+	for i := range p.versions {
+		if p.versions[i] == v {
+			p.versions = append(p.versions[:i], p.versions[i+1:]...)
+			break
+		}
+	}
+
+	s.unmapCodeLocations(v.href)
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listBomComponents(c *call) {
+	v := s.projectVersion(c)
+	if v == nil {
+		return
+	}
+
+	q, ok := s.parseListQuery(c, "componentName")
+	if !ok {
+		return
+	}
+
+	var components []hubapi.BomComponent
+	for _, bc := range v.components {
+		components = append(components, s.renderBomComponent(bc))
+	}
+
+	writeList(s, c, q, components, func(bc hubapi.BomComponent) string { return bc.ComponentName })
+}
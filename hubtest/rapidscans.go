@@ -0,0 +1,193 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubtest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/blackducksoftware/hub-client-go/hubclient"
+)
+
+const (
+	headerBdMode          = "X-BD-MODE"
+	headerBdDocumentCount = "X-BD-DOCUMENT-COUNT"
+)
+
+// RapidScan is a rapid scan received by the Server, see Server.RapidScans
+type RapidScan struct {
+	// URL is the upload endpoint of the scan returned when it was started
+	URL string
+	// Header is the BDIO header document the scan was started with
+	Header string
+	// Documents are the BDIO documents uploaded, in order
+	Documents []string
+	// Finished is set once the upload of the documents has been completed
+	Finished bool
+}
+
+type rapidScan struct {
+	id         string
+	scan       RapidScan
+	pending    int
+	components []hubapi.RapidScanComponent
+}
+
+// SetRapidScanResult sets the components found by the rapid scans finished from now on
+func (s *Server) SetRapidScanResult(components ...hubapi.RapidScanComponent) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.rapidScanResult = components
+}
+
+// SetRapidScanPolls sets how many times the result of a finished rapid scan is polled in vain,
+// answered with a 404, before it is ready. It is ready at once by default.
+func (s *Server) SetRapidScanPolls(polls int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.rapidScanPolls = polls
+}
+
+// RapidScans returns the rapid scans started so far, oldest first
+func (s *Server) RapidScans() []RapidScan {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	scans := make([]RapidScan, 0, len(s.rapidScans))
+	for _, scan := range s.rapidScans {
+		copied := scan.scan
+		copied.Documents = append([]string(nil), scan.scan.Documents...)
+		scans = append(scans, copied)
+	}
+
+	return scans
+}
+
+func (s *Server) startRapidScan(c *call) {
+	if contentType := c.r.Header.Get(hubclient.HeaderNameContentType); contentType != hubapi.ContentTypeRapidScanRequest {
+		s.writeError(c.w, http.StatusUnsupportedMediaType, "{central.media_type_not_supported}",
+			fmt.Sprintf("a rapid scan is started with %s, not %s", hubapi.ContentTypeRapidScanRequest, contentType))
+		return
+	}
+
+	header, _ := ioutil.ReadAll(c.r.Body)
+	id := s.newID()
+	scan := &rapidScan{
+		id: id,
+		scan: RapidScan{
+			URL:    s.href(hubapi.DeveloperScansApi + "/" + id),
+			Header: string(header),
+		},
+	}
+	s.rapidScans = append(s.rapidScans, scan)
+
+	s.writeCreated(c.w, scan.scan.URL)
+}
+
+// rapidScan returns the rapid scan addressed by the call, or writes a 404 and returns nil
+func (s *Server) rapidScan(c *call) *rapidScan {
+	for _, scan := range s.rapidScans {
+		if scan.id == c.ids[0] {
+			return scan
+		}
+	}
+
+	s.writeError(c.w, http.StatusNotFound, "{central.developer_scan_not_found}", fmt.Sprintf("no rapid scan with id %s", c.ids[0]))
+	return nil
+}
+
+// uploadRapidScan receives the BDIO documents of a scan, one per request in append mode,
+// until a request in finish mode completes the scan
+func (s *Server) uploadRapidScan(c *call) {
+	scan := s.rapidScan(c)
+	if scan == nil {
+		return
+	}
+
+	if scan.scan.Finished {
+		s.writeError(c.w, http.StatusConflict, "{central.developer_scan_finished}", "the upload of the rapid scan is already finished")
+		return
+	}
+
+	count, err := strconv.Atoi(c.r.Header.Get(headerBdDocumentCount))
+	if err != nil || count < 0 {
+		s.writeError(c.w, http.StatusBadRequest, "{central.invalid_parameter}",
+			fmt.Sprintf("invalid %s %q", headerBdDocumentCount, c.r.Header.Get(headerBdDocumentCount)))
+		return
+	}
+
+	switch mode := c.r.Header.Get(headerBdMode); mode {
+	case "append":
+		if len(scan.scan.Documents) >= count {
+			s.writeError(c.w, http.StatusBadRequest, "{central.developer_scan_too_many_documents}",
+				fmt.Sprintf("the rapid scan already has the %d documents announced", count))
+			return
+		}
+
+		document, _ := ioutil.ReadAll(c.r.Body)
+		scan.scan.Documents = append(scan.scan.Documents, string(document))
+	case "finish":
+		if len(scan.scan.Documents) != count {
+			s.writeError(c.w, http.StatusBadRequest, "{central.developer_scan_documents_missing}",
+				fmt.Sprintf("%d documents were announced but %d uploaded", count, len(scan.scan.Documents)))
+			return
+		}
+
+		scan.scan.Finished = true
+		scan.pending = s.rapidScanPolls
+		scan.components = s.rapidScanResult
+	default:
+		s.writeError(c.w, http.StatusBadRequest, "{central.invalid_parameter}", fmt.Sprintf("invalid %s %q", headerBdMode, mode))
+		return
+	}
+
+	c.w.WriteHeader(http.StatusAccepted)
+}
+
+// getRapidScanResult serves a page of the components found by a finished scan. Unless the
+// nonVulnerableComponents parameter is set, only components with vulnerabilities are included.
+func (s *Server) getRapidScanResult(c *call) {
+	scan := s.rapidScan(c)
+	if scan == nil {
+		return
+	}
+
+	if !scan.scan.Finished || scan.pending > 0 {
+		if scan.scan.Finished {
+			scan.pending--
+		}
+		s.writeError(c.w, http.StatusNotFound, "{central.developer_scan_result_not_ready}", "the result of the rapid scan is not ready yet")
+		return
+	}
+
+	q, ok := s.parseListQuery(c, "componentName")
+	if !ok {
+		return
+	}
+
+	includeAll := c.r.URL.Query().Get("nonVulnerableComponents") == "true"
+
+	var components []hubapi.RapidScanComponent
+	for _, component := range scan.components {
+		if includeAll || len(component.Vulnerabilities) > 0 {
+			components = append(components, component)
+		}
+	}
+
+	writeList(s, c, q, components, func(component hubapi.RapidScanComponent) string { return component.Name })
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubtest
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/blackducksoftware/hub-client-go/hubclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRapidScan(t *testing.T) {
+	server, client := newTestClient(t)
+
+	server.SetRapidScanPolls(2)
+	server.SetRapidScanResult(
+		hubapi.RapidScanComponent{Name: "lodash", Vulnerabilities: []hubapi.ComponentVulnerability{{Name: "CVE-2021-23337"}}},
+		hubapi.RapidScanComponent{Name: "left-pad"},
+		hubapi.RapidScanComponent{Name: "minimist", Vulnerabilities: []hubapi.ComponentVulnerability{{Name: "CVE-2021-44906"}}},
+	)
+
+	err, endpoint := client.StartRapidScan(`{"header": true}`)
+	require.NoError(t, err)
+
+	require.NoError(t, client.UploadBdioFiles(endpoint, []string{`{"doc": 1}`, `{"doc": 2}`}))
+
+	err, result := client.PollRapidScanResults(endpoint, 10*time.Millisecond, 5*time.Second, 1, hubclient.RapidScanOpts{})
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Count)
+	assert.Equal(t, "lodash", result.Components[0].Name)
+	assert.Equal(t, "minimist", result.Components[1].Name)
+
+	err, status, all := client.FetchResults(endpoint, 0, 10, hubclient.RapidScanOpts{IncludeNonVulnerableComponents: true})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 3, all.Count)
+
+	scans := server.RapidScans()
+	require.Len(t, scans, 1)
+	assert.Equal(t, RapidScan{URL: endpoint, Header: `{"header": true}`, Documents: []string{`{"doc": 1}`, `{"doc": 2}`}, Finished: true}, scans[0])
+
+	polls := 0
+	for _, request := range server.Requests() {
+		if request.Path == endpoint[len(server.URL):]+hubapi.FullResultsApi {
+			polls++
+		}
+	}
+	assert.Equal(t, 5, polls)
+}
+
+func TestRapidScanUploadChecksDocumentCount(t *testing.T) {
+	_, client := newTestClient(t)
+
+	err, endpoint := client.StartRapidScan(`{}`)
+	require.NoError(t, err)
+
+	chunks := hubclient.NewArrayChunkIterator([]string{"one", "two"})
+	err = client.UploadBdioFilesByChunk(endpoint, 1, &chunks)
+	var hce *hubclient.HubClientError
+	require.True(t, errors.As(err, &hce))
+	assert.Equal(t, http.StatusBadRequest, hce.StatusCode)
+	assert.Equal(t, "{central.developer_scan_too_many_documents}", hce.HubError.ErrorCode)
+
+	err, status, _ := client.FetchResults(endpoint, 0, 10, hubclient.RapidScanOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, status)
+}
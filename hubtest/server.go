@@ -0,0 +1,617 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hubtest runs an in-process fake Black Duck server for testing code built on hubclient.
+//
+// The server keeps an in-memory model of projects, project versions, BOM components, code locations,
+// users, policy rules, API tokens and rapid scans. It authenticates requests like Black Duck does,
+// with API tokens exchanged for bearer tokens or with the form login, and answers with the media types,
+// _meta links, paging and Location headers of the real API, so that a hubclient.Client can be run
+// against it unchanged. Faults can be injected to test error handling, see Server.InjectFault.
+package hubtest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/blackducksoftware/hub-client-go/hubclient"
+)
+
+// Credentials of the system administrator every Server starts with
+const (
+	DefaultUsername = "sysadmin"
+	DefaultPassword = "blackduck"
+)
+
+// Media types served by the fake server which have no constant in hubapi
+const (
+	ContentTypeBdUserV4 = "application/vnd.blackducksoftware.user-4+json"
+	ContentTypeBdScanV4 = "application/vnd.blackducksoftware.scan-4+json"
+)
+
+const (
+	defaultVersion        = "2024.1.0"
+	defaultBearerTokenTTL = 2 * time.Hour
+	defaultPageLimit      = 10
+	sessionCookieName     = "AUTHORIZATION_BEARER"
+)
+
+// Server is a fake Black Duck server listening on a local address, see NewServer.
+// Its URL and Close are those of the embedded httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	lock           sync.Mutex
+	routes         []route
+	nextID         int
+	version        string
+	bearerTokenTTL time.Duration
+	sessions       map[string]*session
+	faults         []*Fault
+	requests       []RecordedRequest
+	clientToken    string
+
+	projects        []*project
+	users           []*user
+	policyRules     []*policyRule
+	codeLocations   []*codeLocation
+	rapidScans      []*rapidScan
+	rapidScanResult []hubapi.RapidScanComponent
+	rapidScanPolls  int
+}
+
+// RecordedRequest is a request received by the Server, see Server.Requests
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+type session struct {
+	user    *user
+	csrf    string
+	expires time.Time
+}
+
+// call is a request being served, along with the parts of its path matched by wildcards
+type call struct {
+	w         http.ResponseWriter
+	r         *http.Request
+	ids       []string
+	route     *route
+	user      *user
+	mediaType string
+}
+
+type handler func(s *Server, c *call)
+
+type route struct {
+	segments   []string
+	handlers   map[string]handler
+	mediaTypes []string
+}
+
+// NewServer starts a fake Black Duck server with a system administrator account and no other data.
+// The caller must Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		version:        defaultVersion,
+		bearerTokenTTL: defaultBearerTokenTTL,
+		sessions:       map[string]*session{},
+	}
+
+	s.routes = s.buildRoutes()
+	s.Server = httptest.NewServer(s)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.addUser(hubapi.UserRequest{
+		UserName:  DefaultUsername,
+		FirstName: "System",
+		LastName:  "Administrator",
+		Email:     "sysadmin@example.com",
+		Password:  DefaultPassword,
+		Active:    true,
+	})
+
+	return s
+}
+
+// NewClient creates a Client for the server, authenticated with an API token of the system administrator.
+// The options are applied after the credentials, so they may replace them.
+func (s *Server) NewClient(opts ...hubclient.Option) (*hubclient.Client, error) {
+	s.lock.Lock()
+	if s.clientToken == "" {
+		s.clientToken = s.addApiToken(s.users[0], hubapi.ApiToken{Name: "hubtest", Scopes: []string{"read", "write"}}).value
+	}
+	token := s.clientToken
+	s.lock.Unlock()
+
+	return hubclient.New(s.URL, append([]hubclient.Option{hubclient.WithCredentials(hubclient.ApiTokenCredentials{Token: token})}, opts...)...)
+}
+
+// SetVersion sets the version reported by /api/current-version
+func (s *Server) SetVersion(version string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.version = version
+}
+
+// SetBearerTokenTTL sets how long the bearer tokens and sessions issued from now on stay valid, two hours by default
+func (s *Server) SetBearerTokenTTL(ttl time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.bearerTokenTTL = ttl
+}
+
+// ExpireSessions invalidates all bearer tokens and login sessions, so that clients have to authenticate again
+func (s *Server) ExpireSessions() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.sessions = map[string]*session{}
+}
+
+// Requests returns the requests received so far, oldest first
+func (s *Server) Requests() []RecordedRequest {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// ServeHTTP serves a request against the in-memory model
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	s.lock.Lock()
+	s.requests = append(s.requests, RecordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	fault := s.matchFault(r)
+	s.lock.Unlock()
+
+	if fault != nil && fault.Before != nil {
+		fault.Before()
+	}
+
+	if fault != nil && s.injectFault(w, r, fault) {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch r.URL.Path {
+	case hubapi.SecurityApi:
+		s.login(w, r)
+		return
+	case hubapi.AuthenticateApi:
+		s.authenticate(w, r)
+		return
+	case hubapi.CurrentVersionApi:
+		s.writeJSON(w, http.StatusOK, "application/json", hubapi.CurrentVersion{
+			Version: s.version,
+			Meta:    hubapi.Meta{Href: s.href(hubapi.CurrentVersionApi), Allow: []string{http.MethodGet}},
+		})
+		return
+	case hubapi.ReadinessApi, hubapi.LivenessApi:
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	c := &call{w: w, r: r}
+	rt, ok := s.match(c)
+	c.route = rt
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "{central.resource_not_found}", fmt.Sprintf("no resource at %s", r.URL.Path))
+		return
+	}
+
+	if c.user = s.authorize(w, r); c.user == nil {
+		return
+	}
+
+	h, ok := rt.handlers[r.Method]
+	if !ok {
+		w.Header().Set("Allow", strings.Join(rt.allow(), ", "))
+		s.writeError(w, http.StatusMethodNotAllowed, "{central.method_not_allowed}", fmt.Sprintf("%s is not supported by %s", r.Method, r.URL.Path))
+		return
+	}
+
+	if c.mediaType, ok = negotiate(r.Header.Get(hubclient.HeaderNameAccept), rt.mediaTypes); !ok {
+		s.writeError(w, http.StatusNotAcceptable, "{central.media_type_not_acceptable}",
+			fmt.Sprintf("%s is not available as %s", r.URL.Path, r.Header.Get(hubclient.HeaderNameAccept)))
+		return
+	}
+
+	h(s, c)
+}
+
+// match finds the route of the request path, filling in the ids of the call
+func (s *Server) match(c *call) (*route, bool) {
+	segments := strings.Split(strings.Trim(c.r.URL.Path, "/"), "/")
+
+	for i := range s.routes {
+		rt := &s.routes[i]
+		if len(rt.segments) != len(segments) {
+			continue
+		}
+
+		var ids []string
+		for j, segment := range rt.segments {
+			if segment == "*" {
+				ids = append(ids, segments[j])
+			} else if segment != segments[j] {
+				ids = nil
+				break
+			}
+			if j == len(segments)-1 {
+				c.ids = ids
+				return rt, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func (rt *route) allow() []string {
+	var methods []string
+	for method := range rt.handlers {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// negotiate picks the media type of the response from the Accept header. Black Duck answers
+// with 406 when none of the accepted types is one of its own for the resource.
+func negotiate(accept string, mediaTypes []string) (string, bool) {
+	if accept == "" {
+		return mediaTypes[0], true
+	}
+
+	for _, value := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+
+		switch mediaType {
+		case "*/*", "application/*", "application/json":
+			return mediaTypes[0], true
+		}
+
+		for _, supported := range mediaTypes {
+			if mediaType == supported {
+				return supported, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// login serves the form login, which starts a session tracked by a cookie
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.writeError(w, http.StatusMethodNotAllowed, "{central.method_not_allowed}", "the login only accepts POST")
+		return
+	}
+
+	username, password := r.PostFormValue("j_username"), r.PostFormValue("j_password")
+	for _, u := range s.users {
+		if u.user.UserName == username && u.password == password && u.user.Active {
+			token, ses := s.newSession(u)
+			http.SetCookie(w, &http.Cookie{Name: sessionCookieName, Value: token, Path: "/", HttpOnly: true})
+			w.Header().Set(hubclient.HeaderNameCsrfToken, ses.csrf)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	s.writeError(w, http.StatusUnauthorized, "{central.authentication_failed}", "invalid username or password")
+}
+
+// authenticate exchanges an API token for a bearer token
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.writeError(w, http.StatusMethodNotAllowed, "{central.method_not_allowed}", "token authentication only accepts POST")
+		return
+	}
+
+	value := strings.TrimPrefix(r.Header.Get(hubclient.HeaderNameAuthorization), "token ")
+	for _, u := range s.users {
+		for _, t := range u.tokens {
+			if t.value == value && u.user.Active {
+				token, ses := s.newSession(u)
+				w.Header().Set(hubclient.HeaderNameCsrfToken, ses.csrf)
+				s.writeJSON(w, http.StatusOK, "application/json", hubclient.BearerTokenResponse{
+					BearerToken:           token,
+					ExpiresInMilliseconds: s.bearerTokenTTL.Milliseconds(),
+				})
+				return
+			}
+		}
+	}
+
+	s.writeError(w, http.StatusUnauthorized, "{central.authentication_failed}", "invalid API token")
+}
+
+func (s *Server) newSession(u *user) (string, *session) {
+	token := randomToken()
+	ses := &session{user: u, csrf: randomToken(), expires: time.Now().Add(s.bearerTokenTTL)}
+	s.sessions[token] = ses
+	return token, ses
+}
+
+// authorize returns the user of the session of the request, or writes the error response and returns nil.
+// Like Black Duck, requests changing data must carry the CSRF token issued with the session.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) *user {
+	token := ""
+	if auth := r.Header.Get(hubclient.HeaderNameAuthorization); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	} else if cookie, err := r.Cookie(sessionCookieName); err == nil {
+		token = cookie.Value
+	}
+
+	ses, ok := s.sessions[token]
+	if !ok || time.Now().After(ses.expires) {
+		delete(s.sessions, token)
+		s.writeError(w, http.StatusUnauthorized, "{central.authentication_required}", "authentication required")
+		return nil
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		if r.Header.Get(hubclient.HeaderNameCsrfToken) != ses.csrf {
+			s.writeError(w, http.StatusForbidden, "{central.csrf_token_invalid}", "CSRF token is missing or invalid")
+			return nil
+		}
+	}
+
+	return ses.user
+}
+
+// newID returns a new resource id, shaped like the UUIDs used by Black Duck
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.nextID)
+}
+
+// href returns the absolute URL of the given API path
+func (s *Server) href(path string) string {
+	return s.URL + path
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, mediaType string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "{central.internal_error}", err.Error())
+		return
+	}
+
+	w.Header().Set(hubclient.HeaderNameContentType, mediaType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// writeError writes an error response in the format of Black Duck, with a log reference pointing to the request
+func (s *Server) writeError(w http.ResponseWriter, status int, errorCode string, message string) {
+	data, _ := json.Marshal(hubclient.HubResponseError{
+		ErrorMessage: message,
+		ErrorCode:    errorCode,
+		LogRef:       fmt.Sprintf("hubtest.%d", len(s.requests)),
+	})
+
+	w.Header().Set(hubclient.HeaderNameContentType, "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// writeCreated answers a successful POST with the Location of the new resource
+func (s *Server) writeCreated(w http.ResponseWriter, location string) {
+	w.Header().Set("Location", location)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// decode reads the JSON body of the request into v, or writes the error response and returns false
+func (s *Server) decode(c *call, v interface{}) bool {
+	if err := json.NewDecoder(c.r.Body).Decode(v); err != nil {
+		s.writeError(c.w, http.StatusBadRequest, "{central.invalid_json}", fmt.Sprintf("unable to read request body: %v", err))
+		return false
+	}
+
+	return true
+}
+
+// itemList is the body of every list response
+type itemList struct {
+	TotalCount     int           `json:"totalCount"`
+	Items          interface{}   `json:"items"`
+	AppliedFilters []interface{} `json:"appliedFilters,omitempty"`
+	Meta           hubapi.Meta   `json:"_meta"`
+}
+
+// listQuery holds the paging, filtering and sorting parameters of a list request
+type listQuery struct {
+	offset, limit int
+	filter        string
+	descending    bool
+	sorted        bool
+}
+
+// parseListQuery reads the list parameters of the request. Filters (q=key:value) and sorting (sort=key asc|desc)
+// are supported on the given key only. On an invalid parameter the error response is written and false returned.
+func (s *Server) parseListQuery(c *call, key string) (listQuery, bool) {
+	query := c.r.URL.Query()
+	q := listQuery{limit: defaultPageLimit}
+
+	for name, target := range map[string]*int{"offset": &q.offset, "limit": &q.limit} {
+		if value := query.Get(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				s.writeError(c.w, http.StatusBadRequest, "{central.invalid_parameter}", fmt.Sprintf("invalid %s %q", name, value))
+				return q, false
+			}
+			*target = n
+		}
+	}
+
+	if value := query.Get("q"); value != "" {
+		parts := strings.SplitN(value, ":", 2)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], key) {
+			s.writeError(c.w, http.StatusBadRequest, "{central.invalid_parameter}", fmt.Sprintf("unsupported filter %q, use %s:<value>", value, key))
+			return q, false
+		}
+		q.filter = strings.ToLower(parts[1])
+	}
+
+	if value := query.Get("sort"); value != "" {
+		fields := strings.Fields(value)
+		if len(fields) > 2 || !strings.EqualFold(fields[0], key) ||
+			(len(fields) == 2 && !strings.EqualFold(fields[1], "asc") && !strings.EqualFold(fields[1], "desc")) {
+			s.writeError(c.w, http.StatusBadRequest, "{central.invalid_parameter}", fmt.Sprintf("unsupported sort %q, use %s asc|desc", value, key))
+			return q, false
+		}
+		q.sorted = true
+		q.descending = len(fields) == 2 && strings.EqualFold(fields[1], "desc")
+	}
+
+	return q, true
+}
+
+// writeList writes a page of the items which pass the filter of the list query, sorted by name if asked to
+func writeList[T any](s *Server, c *call, q listQuery, items []T, name func(T) string) {
+	var matching []T
+	for _, item := range items {
+		if q.filter == "" || strings.Contains(strings.ToLower(name(item)), q.filter) {
+			matching = append(matching, item)
+		}
+	}
+
+	if q.sorted {
+		sort.SliceStable(matching, func(i, j int) bool {
+			if q.descending {
+				return name(matching[i]) > name(matching[j])
+			}
+			return name(matching[i]) < name(matching[j])
+		})
+	}
+
+	page := []T{}
+	for i := q.offset; i < len(matching) && i < q.offset+q.limit; i++ {
+		page = append(page, matching[i])
+	}
+
+	s.writeJSON(c.w, http.StatusOK, c.mediaType, itemList{
+		TotalCount: len(matching),
+		Items:      page,
+		Meta: hubapi.Meta{
+			Href:  s.href(c.r.URL.Path),
+			Allow: c.route.allow(),
+		},
+	})
+}
+
+// meta builds the _meta of a resource
+func meta(href string, allow []string, links ...hubapi.ResourceLink) hubapi.Meta {
+	return hubapi.Meta{Href: href, Allow: allow, Links: links}
+}
+
+func link(rel, href string) hubapi.ResourceLink {
+	return hubapi.ResourceLink{Rel: rel, Href: href}
+}
+
+// now returns the current time as the server stores it
+func now() *time.Time {
+	t := time.Now().UTC().Truncate(time.Millisecond)
+	return &t
+}
+
+func (s *Server) buildRoutes() []route {
+	projectTypes := []string{hubapi.ContentTypeBdProjectDetailV4, hubapi.ContentTypeBdProjectDetailV5}
+	versionTypes := []string{hubapi.ContentTypeBdProjectDetailV5, hubapi.ContentTypeBdProjectDetailV4}
+	userTypes := []string{ContentTypeBdUserV4}
+	bomTypes := []string{hubapi.ContentTypeBdBomV6}
+	scanTypes := []string{ContentTypeBdScanV4}
+	policyTypes := []string{hubapi.ContentTypeBdPolicyV5}
+	rapidScanTypes := []string{hubapi.ContentTypeRapidScanResults}
+
+	return []route{
+		newRoute("/api/projects", projectTypes, http.MethodGet, (*Server).listProjects, http.MethodPost, (*Server).createProject),
+		newRoute("/api/projects/*", projectTypes, http.MethodGet, (*Server).getProject, http.MethodPut, (*Server).updateProject, http.MethodDelete, (*Server).deleteProject),
+		newRoute("/api/projects/*/users", userTypes, http.MethodGet, (*Server).listProjectUsers, http.MethodPost, (*Server).assignProjectUser),
+		newRoute("/api/projects/*/versions", versionTypes, http.MethodGet, (*Server).listProjectVersions, http.MethodPost, (*Server).createProjectVersion),
+		newRoute("/api/projects/*/versions/*", versionTypes, http.MethodGet, (*Server).getProjectVersion, http.MethodPut, (*Server).updateProjectVersion, http.MethodDelete, (*Server).deleteProjectVersion),
+		newRoute("/api/projects/*/versions/*/components", bomTypes, http.MethodGet, (*Server).listBomComponents),
+		newRoute("/api/projects/*/versions/*/codelocations", scanTypes, http.MethodGet, (*Server).listProjectVersionCodeLocations),
+		newRoute("/api/codelocations", scanTypes, http.MethodGet, (*Server).listCodeLocations),
+		newRoute("/api/codelocations/*", scanTypes, http.MethodGet, (*Server).getCodeLocation, http.MethodDelete, (*Server).deleteCodeLocation),
+		newRoute("/api/codelocations/*/scan-summaries", scanTypes, http.MethodGet, (*Server).listScanSummaries),
+		newRoute("/api/users", userTypes, http.MethodGet, (*Server).listUsers, http.MethodPost, (*Server).createUser),
+		newRoute("/api/users/*", userTypes, http.MethodGet, (*Server).getUser, http.MethodDelete, (*Server).deleteUser),
+		newRoute("/api/current-user", userTypes, http.MethodGet, (*Server).getCurrentUser),
+		newRoute("/api/current-user/tokens", userTypes, http.MethodGet, (*Server).listApiTokens, http.MethodPost, (*Server).createApiToken),
+		newRoute("/api/current-user/tokens/*", userTypes, http.MethodGet, (*Server).getApiToken, http.MethodDelete, (*Server).deleteApiToken),
+		newRoute("/api/policy-rules", policyTypes, http.MethodGet, (*Server).listPolicyRules, http.MethodPost, (*Server).createPolicyRule),
+		newRoute("/api/policy-rules/*", policyTypes, http.MethodGet, (*Server).getPolicyRule, http.MethodPut, (*Server).updatePolicyRule, http.MethodDelete, (*Server).deletePolicyRule),
+		newRoute("/api/developer-scans", rapidScanTypes, http.MethodPost, (*Server).startRapidScan),
+		newRoute("/api/developer-scans/*", rapidScanTypes, http.MethodPut, (*Server).uploadRapidScan),
+		newRoute("/api/developer-scans/*/full-result", rapidScanTypes, http.MethodGet, (*Server).getRapidScanResult),
+	}
+}
+
+// newRoute builds a route from a path with * wildcards, its media types and pairs of methods and handlers
+func newRoute(path string, mediaTypes []string, methodHandlers ...interface{}) route {
+	rt := route{
+		segments:   strings.Split(strings.Trim(path, "/"), "/"),
+		handlers:   map[string]handler{},
+		mediaTypes: mediaTypes,
+	}
+
+	for i := 0; i < len(methodHandlers); i += 2 {
+		rt.handlers[methodHandlers[i].(string)] = methodHandlers[i+1].(func(*Server, *call))
+	}
+
+	return rt
+}
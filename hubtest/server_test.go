@@ -0,0 +1,312 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubtest
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/blackducksoftware/hub-client-go/hubclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, opts ...hubclient.Option) (*Server, *hubclient.Client) {
+	server := NewServer()
+	t.Cleanup(server.Close)
+
+	client, err := server.NewClient(opts...)
+	require.NoError(t, err)
+
+	return server, client
+}
+
+func statusCode(err error) int {
+	var hce *hubclient.HubClientError
+	if errors.As(err, &hce) {
+		return hce.StatusCode
+	}
+	return 0
+}
+
+func versionRequest(name string) *hubapi.ProjectVersionRequest {
+	return &hubapi.ProjectVersionRequest{
+		VersionName:  name,
+		Phase:        hubapi.ProjectVersionPhaseDevelopment,
+		Distribution: hubapi.ProjectVersionDistributionInternal,
+	}
+}
+
+func TestProjectsAndVersions(t *testing.T) {
+	server, client := newTestClient(t)
+
+	location, err := client.CreateProject(&hubapi.ProjectRequest{Name: "demo", Description: "a demo", VersionRequest: versionRequest("1.0")})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(location, server.URL+hubapi.ProjectsApi+"/"))
+
+	project, err := client.GetProject(hubapi.ResourceLink{Href: location})
+	require.NoError(t, err)
+	assert.Equal(t, "demo", project.Name)
+	assert.Equal(t, DefaultUsername, project.CreatedBy)
+	assert.Equal(t, location, project.Meta.Href)
+
+	versionsLink, err := project.GetProjectVersionsLink()
+	require.NoError(t, err)
+
+	_, err = client.CreateProjectVersion(*versionsLink, versionRequest("1.0"))
+	assert.Equal(t, http.StatusConflict, statusCode(err))
+
+	_, err = client.CreateProjectVersion(*versionsLink, versionRequest("2.0"))
+	require.NoError(t, err)
+
+	q := "versionName:2."
+	versions, err := client.ListProjectVersions(*versionsLink, &hubapi.GetListOptions{Q: &q})
+	require.NoError(t, err)
+	require.Equal(t, 1, versions.TotalCount)
+	assert.Equal(t, "2.0", versions.Items[0].VersionName)
+
+	projectLink, err := versions.Items[0].GetProjectLink()
+	require.NoError(t, err)
+	assert.Equal(t, location, projectLink.Href)
+
+	_, err = client.CreateProject(&hubapi.ProjectRequest{Name: "DEMO"})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusConflict, statusCode(err))
+
+	require.NoError(t, client.DeleteProject(location))
+	_, err = client.GetProject(hubapi.ResourceLink{Href: location})
+	assert.Equal(t, http.StatusNotFound, statusCode(err))
+}
+
+func TestProjectPaging(t *testing.T) {
+	server, client := newTestClient(t)
+
+	for _, name := range []string{"delta", "alpha", "echo", "charlie", "bravo"} {
+		_, err := server.AddProject(hubapi.ProjectRequest{Name: name})
+		require.NoError(t, err)
+	}
+
+	limit, sort := 2, "name asc"
+	pager := client.ProjectsPager(&hubapi.GetListOptions{Limit: &limit, Sort: &sort})
+
+	var names []string
+	for pager.Next() {
+		names = append(names, pager.Item().Name)
+	}
+	require.NoError(t, pager.Err())
+	assert.Equal(t, []string{"alpha", "bravo", "charlie", "delta", "echo"}, names)
+	assert.Equal(t, 5, pager.Total())
+
+	requests := 0
+	for _, request := range server.Requests() {
+		if request.Path == hubapi.ProjectsApi {
+			requests++
+		}
+	}
+	assert.Equal(t, 3, requests)
+
+	q := "title:alpha"
+	_, err := client.ListProjects(&hubapi.GetListOptions{Q: &q})
+	assert.Equal(t, http.StatusBadRequest, statusCode(err))
+}
+
+func TestBomComponentsAndCodeLocations(t *testing.T) {
+	server, client := newTestClient(t)
+
+	projectURL, err := server.AddProject(hubapi.ProjectRequest{Name: "demo", VersionRequest: versionRequest("1.0")})
+	require.NoError(t, err)
+
+	project, err := client.GetProject(hubapi.ResourceLink{Href: projectURL})
+	require.NoError(t, err)
+	versionsLink, _ := project.GetProjectVersionsLink()
+	versions, err := client.ListProjectVersions(*versionsLink, nil)
+	require.NoError(t, err)
+	version := versions.Items[0]
+
+	_, err = server.AddBomComponent(version.Meta.Href, hubapi.BomComponent{ComponentName: "openssl", ComponentVersionName: "3.0.0"})
+	require.NoError(t, err)
+	_, err = server.AddCodeLocation(hubapi.CodeLocation{Name: "demo/scan", MappedProjectVersion: version.Meta.Href})
+	require.NoError(t, err)
+	_, err = server.AddCodeLocation(hubapi.CodeLocation{Name: "unmapped"})
+	require.NoError(t, err)
+
+	componentsLink, err := version.GetComponentsLink()
+	require.NoError(t, err)
+	components, err := client.ListProjectVersionComponents(*componentsLink)
+	require.NoError(t, err)
+	require.Len(t, components.Items, 1)
+	assert.Equal(t, "openssl", components.Items[0].ComponentName)
+	assert.True(t, strings.HasPrefix(components.Items[0].Meta.Href, version.Meta.Href+"/components/"))
+
+	codeLocationsLink, err := version.GetCodeLocationsLink()
+	require.NoError(t, err)
+	locations, err := client.ListCodeLocations(*codeLocationsLink, nil)
+	require.NoError(t, err)
+	require.Len(t, locations.Items, 1)
+
+	scansLink, err := locations.Items[0].GetScanSummariesLink()
+	require.NoError(t, err)
+	scans, err := client.ListScanSummaries(*scansLink)
+	require.NoError(t, err)
+	require.Len(t, scans.Items, 1)
+	assert.Equal(t, "COMPLETE", scans.Items[0].Status)
+
+	all, err := client.ListAllCodeLocations(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, all.TotalCount)
+
+	require.NoError(t, client.DeleteProjectVersion(version.Meta.Href))
+	location, err := client.GetCodeLocation(hubapi.ResourceLink{Href: locations.Items[0].Meta.Href})
+	require.NoError(t, err)
+	assert.Empty(t, location.MappedProjectVersion)
+}
+
+func TestUsersAndLogin(t *testing.T) {
+	server, client := newTestClient(t)
+
+	user, err := client.CreateUser(&hubapi.UserRequest{UserName: "jdoe", Password: "secret", Active: true})
+	require.NoError(t, err)
+	assert.Equal(t, "jdoe", user.UserName)
+
+	_, err = client.CreateUser(&hubapi.UserRequest{UserName: "jdoe"})
+	assert.Equal(t, http.StatusConflict, statusCode(err))
+
+	projectURL, err := server.AddProject(hubapi.ProjectRequest{Name: "demo"})
+	require.NoError(t, err)
+	project, err := client.GetProject(hubapi.ResourceLink{Href: projectURL})
+	require.NoError(t, err)
+	usersLink, err := project.GetProjectUsersLink()
+	require.NoError(t, err)
+	_, err = client.AssignUserToProject(*usersLink, &hubapi.UserAssignmentRequest{User: user.Meta.Href})
+	require.NoError(t, err)
+
+	_, err = hubclient.New(server.URL, hubclient.WithCredentials(hubclient.UsernamePasswordCredentials{Username: "jdoe", Password: "wrong"}))
+	assert.Equal(t, http.StatusUnauthorized, statusCode(err))
+
+	session, err := hubclient.New(server.URL, hubclient.WithCredentials(hubclient.UsernamePasswordCredentials{Username: "jdoe", Password: "secret"}))
+	require.NoError(t, err)
+
+	current, err := session.GetCurrentUser()
+	require.NoError(t, err)
+	assert.Equal(t, "jdoe", current.UserName)
+	assert.Equal(t, user.Meta.Href, current.User)
+
+	// the session is authenticated by its cookie, and changes need its CSRF token
+	_, err = session.CreateProject(&hubapi.ProjectRequest{Name: "by-jdoe"})
+	require.NoError(t, err)
+
+	users, err := client.ListUsers(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, users.TotalCount)
+}
+
+func TestApiTokens(t *testing.T) {
+	server, client := newTestClient(t)
+
+	location, token, err := client.CreateApiToken("ci", "for the CI", true)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+
+	tokenClient, err := hubclient.New(server.URL, hubclient.WithCredentials(hubclient.ApiTokenCredentials{Token: token}))
+	require.NoError(t, err)
+
+	tokens, err := tokenClient.ListApiTokens(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, tokens.TotalCount)
+	assert.Equal(t, location, tokens.Items[1].Meta.Href)
+
+	require.NoError(t, client.DeleteApiToken(location))
+
+	_, err = hubclient.New(server.URL, hubclient.WithCredentials(hubclient.ApiTokenCredentials{Token: token}))
+	assert.Equal(t, http.StatusUnauthorized, statusCode(err))
+}
+
+func TestPolicyRules(t *testing.T) {
+	_, client := newTestClient(t)
+
+	request := &hubapi.PolicyRuleRequest{
+		Name:     "no-gpl",
+		Enabled:  true,
+		Severity: "BLOCKER",
+		Expression: hubapi.PolicyExpression{
+			Operator: "AND",
+			Expressions: []hubapi.Expression{{
+				Name:       "SINGLE_LICENSE",
+				Operation:  "EQ",
+				Parameters: hubapi.ExpressionParameter{Values: []string{"GPL-3.0"}},
+			}},
+		},
+	}
+
+	location, err := client.CreatePolicyRule(request)
+	require.NoError(t, err)
+
+	rules, err := client.ListPolicyRules(nil)
+	require.NoError(t, err)
+	require.Len(t, rules.Items, 1)
+	assert.Equal(t, location, rules.Items[0].Meta.Href)
+	assert.Equal(t, request.Expression, rules.Items[0].Expression)
+
+	_, err = client.CreatePolicyRule(request)
+	assert.Equal(t, http.StatusConflict, statusCode(err))
+
+	require.NoError(t, client.DeletePolicyRule(location))
+}
+
+func TestAuthentication(t *testing.T) {
+	server, client := newTestClient(t)
+
+	anonymous, err := hubclient.New(server.URL)
+	require.NoError(t, err)
+	_, err = anonymous.ListProjects(nil)
+	assert.Equal(t, http.StatusUnauthorized, statusCode(err))
+
+	version, err := anonymous.CurrentVersion()
+	require.NoError(t, err)
+	assert.Equal(t, defaultVersion, version.Version)
+
+	// the client authenticates again with its API token once its bearer token is rejected
+	server.ExpireSessions()
+	_, err = client.ListProjects(nil)
+	require.NoError(t, err)
+
+	bearer, err := hubclient.New(server.URL, hubclient.WithCredentials(hubclient.BearerTokenCredentials{Token: "unknown"}))
+	require.NoError(t, err)
+	_, err = bearer.ListProjects(nil)
+	assert.Equal(t, http.StatusUnauthorized, statusCode(err))
+}
+
+func TestMediaTypes(t *testing.T) {
+	server, client := newTestClient(t)
+
+	_, err := server.AddProject(hubapi.ProjectRequest{Name: "demo"})
+	require.NoError(t, err)
+
+	var list hubapi.ProjectList
+	require.NoError(t, client.HttpGetJSON(server.URL+hubapi.ProjectsApi, &list, http.StatusOK))
+	assert.Equal(t, 1, list.TotalCount)
+
+	requests := server.Requests()
+	assert.Equal(t, hubapi.ContentTypeBdProjectDetailV4, requests[len(requests)-1].Header.Get(hubclient.HeaderNameAccept))
+
+	err = client.HttpGetJSON(server.URL+hubapi.ProjectsApi, &list, http.StatusOK, hubapi.ContentTypeBdPolicyV5)
+	assert.Equal(t, http.StatusNotAcceptable, statusCode(err))
+
+	err = client.HttpPutJSON(server.URL+hubapi.ProjectsApi, &hubapi.ProjectRequest{Name: "x"}, "application/json", http.StatusNoContent)
+	assert.Equal(t, http.StatusMethodNotAllowed, statusCode(err))
+}
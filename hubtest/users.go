@@ -0,0 +1,306 @@
+// Copyright 2024 Synopsys, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubtest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/blackducksoftware/hub-client-go/hubapi"
+	"github.com/pkg/errors"
+)
+
+type user struct {
+	id       string
+	href     string
+	user     hubapi.User
+	password string
+	tokens   []*apiToken
+}
+
+type apiToken struct {
+	id    string
+	href  string
+	token hubapi.ApiToken
+	value string
+}
+
+// AddUser creates a user, who can log in with the password of the request. It returns the URL of the user.
+func (s *Server) AddUser(request hubapi.UserRequest) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if status, code, message := s.validateUser(request); status != 0 {
+		return "", errors.Errorf("%d %s: %s", status, code, message)
+	}
+
+	return s.addUser(request).href, nil
+}
+
+// AddApiToken creates an API token of the user with the given name. It returns the secret value of the token,
+// which authenticates as that user.
+func (s *Server) AddApiToken(username string, token hubapi.ApiToken) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, u := range s.users {
+		if u.user.UserName == username {
+			return s.addApiToken(u, token).value, nil
+		}
+	}
+
+	return "", errors.Errorf("no user named %s", username)
+}
+
+func (s *Server) validateUser(request hubapi.UserRequest) (int, string, string) {
+	if strings.TrimSpace(request.UserName) == "" {
+		return http.StatusBadRequest, "{central.constraint_violation.user_name_required}", "the user name is required"
+	}
+
+	for _, u := range s.users {
+		if strings.EqualFold(u.user.UserName, request.UserName) {
+			return http.StatusConflict, "{central.constraint_violation.user_name_duplicate_not_allowed}",
+				fmt.Sprintf("a user named %s already exists", request.UserName)
+		}
+	}
+
+	return 0, "", ""
+}
+
+func (s *Server) addUser(request hubapi.UserRequest) *user {
+	id := s.newID()
+	u := &user{
+		id:       id,
+		href:     s.href(hubapi.UsersApi + "/" + id),
+		password: request.Password,
+		user: hubapi.User{
+			UserName:  request.UserName,
+			FirstName: request.FirstName,
+			LastName:  request.LastName,
+			Email:     request.Email,
+			Active:    request.Active,
+		},
+	}
+	u.user.User = u.href
+	s.users = append(s.users, u)
+
+	return u
+}
+
+func (s *Server) addApiToken(u *user, token hubapi.ApiToken) *apiToken {
+	id := s.newID()
+	t := &apiToken{
+		id:    id,
+		href:  s.href(hubapi.CurrentUserTokensApi + "/" + id),
+		token: token,
+		value: randomToken(),
+	}
+	u.tokens = append(u.tokens, t)
+
+	return t
+}
+
+func (s *Server) userByHref(href string) *user {
+	for _, u := range s.users {
+		if u.href == href {
+			return u
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) renderUser(u *user) hubapi.User {
+	rendered := u.user
+	rendered.Meta = meta(u.href, []string{http.MethodGet, http.MethodDelete})
+
+	return rendered
+}
+
+func (s *Server) renderApiToken(t *apiToken) hubapi.ApiTokenWithMeta {
+	return hubapi.ApiTokenWithMeta{
+		ApiToken: t.token,
+		Meta:     meta(t.href, []string{http.MethodGet, http.MethodDelete}),
+	}
+}
+
+func (s *Server) listUsers(c *call) {
+	q, ok := s.parseListQuery(c, "userName")
+	if !ok {
+		return
+	}
+
+	var users []hubapi.User
+	for _, u := range s.users {
+		users = append(users, s.renderUser(u))
+	}
+
+	writeList(s, c, q, users, func(u hubapi.User) string { return u.UserName })
+}
+
+func (s *Server) createUser(c *call) {
+	var request hubapi.UserRequest
+	if !s.decode(c, &request) {
+		return
+	}
+
+	if status, code, message := s.validateUser(request); status != 0 {
+		s.writeError(c.w, status, code, message)
+		return
+	}
+
+	u := s.addUser(request)
+	c.w.Header().Set("Location", u.href)
+	s.writeJSON(c.w, http.StatusCreated, c.mediaType, s.renderUser(u))
+}
+
+// user returns the user addressed by the call, or writes a 404 and returns nil
+func (s *Server) user(c *call) *user {
+	for _, u := range s.users {
+		if u.id == c.ids[0] {
+			return u
+		}
+	}
+
+	s.writeError(c.w, http.StatusNotFound, "{central.user_not_found}", fmt.Sprintf("no user with id %s", c.ids[0]))
+	return nil
+}
+
+func (s *Server) getUser(c *call) {
+	if u := s.user(c); u != nil {
+		s.writeJSON(c.w, http.StatusOK, c.mediaType, s.renderUser(u))
+	}
+}
+
+func (s *Server) deleteUser(c *call) {
+	u := s.user(c)
+	if u == nil {
+		return
+	}
+
+	if u == c.user {
+		s.writeError(c.w, http.StatusBadRequest, "{central.constraint_violation.user_delete_self}", "users cannot delete themselves")
+		return
+	}
+
+	for i := range s.users {
+		if s.users[i] == u {
+			s.users = append(s.users[:i], s.users[i+1:]...)
+			break
+		}
+	}
+
+	for token, ses := range s.sessions {
+		if ses.user == u {
+			delete(s.sessions, token)
+		}
+	}
+
+	for _, p := range s.projects {
+		for i := range p.users {
+			if p.users[i] == u {
+				p.users = append(p.users[:i], p.users[i+1:]...)
+				break
+			}
+		}
+	}
+
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getCurrentUser(c *call) {
+	u := c.user
+	s.writeJSON(c.w, http.StatusOK, c.mediaType, hubapi.CurrentUserResponse{
+		UserName:  u.user.UserName,
+		FirstName: u.user.FirstName,
+		LastName:  u.user.LastName,
+		Email:     u.user.Email,
+		Type:      "INTERNAL",
+		Active:    u.user.Active,
+		User:      u.href,
+		Meta: meta(s.href(hubapi.CurrentUserApi), []string{http.MethodGet},
+			link(hubapi.CurrentUserRelApiTokens, s.href(hubapi.CurrentUserTokensApi)),
+		),
+	})
+}
+
+func (s *Server) listApiTokens(c *call) {
+	q, ok := s.parseListQuery(c, "name")
+	if !ok {
+		return
+	}
+
+	var tokens []hubapi.ApiTokenWithMeta
+	for _, t := range c.user.tokens {
+		tokens = append(tokens, s.renderApiToken(t))
+	}
+
+	writeList(s, c, q, tokens, func(t hubapi.ApiTokenWithMeta) string { return t.Name })
+}
+
+func (s *Server) createApiToken(c *call) {
+	var request hubapi.ApiToken
+	if !s.decode(c, &request) {
+		return
+	}
+
+	if strings.TrimSpace(request.Name) == "" {
+		s.writeError(c.w, http.StatusBadRequest, "{central.constraint_violation.token_name_required}", "the token name is required")
+		return
+	}
+
+	t := s.addApiToken(c.user, request)
+	c.w.Header().Set("Location", t.href)
+	s.writeJSON(c.w, http.StatusCreated, c.mediaType, hubapi.CreateApiTokenResponse{
+		ApiTokenWithMeta: s.renderApiToken(t),
+		Token:            t.value,
+	})
+}
+
+// apiToken returns the API token of the current user addressed by the call, or writes a 404 and returns nil
+func (s *Server) apiToken(c *call) *apiToken {
+	for _, t := range c.user.tokens {
+		if t.id == c.ids[0] {
+			return t
+		}
+	}
+
+	s.writeError(c.w, http.StatusNotFound, "{central.api_token_not_found}", fmt.Sprintf("no API token with id %s", c.ids[0]))
+	return nil
+}
+
+func (s *Server) getApiToken(c *call) {
+	if t := s.apiToken(c); t != nil {
+		s.writeJSON(c.w, http.StatusOK, c.mediaType, s.renderApiToken(t))
+	}
+}
+
+func (s *Server) deleteApiToken(c *call) {
+	t := s.apiToken(c)
+	if t == nil {
+		return
+	}
+
+	tokens := c.user.tokens
+	for i := range tokens {
+		if tokens[i] == t {
+			c.user.tokens = append(tokens[:i], tokens[i+1:]...)
+			break
+		}
+	}
+
+	c.w.WriteHeader(http.StatusNoContent)
+}